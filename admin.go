@@ -0,0 +1,253 @@
+package golog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Schedules the revert of a temporary level; abstracted out for unit testing.
+var afterFunc = func(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
+// An http.Handler to inspect and change the levels of the module loggers at
+// runtime. The zero value is ready to use, e.g.
+//
+//	http.Handle("/debug/golog", &golog.AdminHandler{MaxTTL: time.Hour})
+//
+// GET lists every registered module with its level and prefix as JSON. PUT or
+// POST change the level of a module, taking a JSON body such as
+//
+//	{"module": "db", "level": "VERBOSE", "ttl": "15m"}
+//
+// With a ttl, the level is temporary: once it expires, the module reverts to
+// the level it had before, unless the level was changed again in between.
+// Every change is logged as an INFO line to the "golog" module.
+type AdminHandler struct {
+	// if set, every change must be temporary and last at most MaxTTL, so that
+	// e.g. VERBOSE cannot be left on by accident
+	MaxTTL time.Duration
+
+	// guards overrides, the temporary levels that have not expired yet
+	lock sync.Mutex
+	overrides map[string]*levelOverride
+}
+
+// Represents a temporary level of a module.
+type levelOverride struct {
+	level Level
+	expires time.Time
+
+	// the level the module had before the first override, and whether it was
+	// set as opposed to inherited
+	previous Level
+	explicit bool
+
+	// cancels the scheduled revert
+	cancel func() bool
+}
+
+// Describes a module in the responses of the handler.
+type adminModule struct {
+	Module string `json:"module"`
+	Level Level `json:"level"`
+	Prefix string `json:"prefix"`
+
+	// whether the level was set for the module, as opposed to inherited
+	Explicit bool `json:"explicit"`
+
+	// when a temporary level reverts; omitted for permanent levels
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+// The body of a PUT or POST request.
+type adminRequest struct {
+	Module string `json:"module"`
+	Level *Level `json:"level"`
+	TTL string `json:"ttl"`
+}
+
+func (handler *AdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		handler.writeJSON(w, http.StatusOK, handler.modules())
+
+	case http.MethodPut, http.MethodPost:
+		handler.change(w, r)
+
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (handler *AdminHandler) change(w http.ResponseWriter, r *http.Request) {
+	var request adminRequest
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	if request.Module == "" || request.Level == nil {
+		http.Error(w, "invalid request: module and level are required", http.StatusBadRequest)
+		return
+	}
+
+	var ttl time.Duration
+	if request.TTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(request.TTL); err != nil || ttl <= 0 {
+			http.Error(w, fmt.Sprintf("invalid request: invalid ttl %q", request.TTL), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if handler.MaxTTL > 0 && (ttl == 0 || ttl > handler.MaxTTL) {
+		http.Error(w, fmt.Sprintf("invalid request: ttl must be set and at most %s", handler.MaxTTL), http.StatusBadRequest)
+		return
+	}
+
+	handler.setLevel(request.Module, *request.Level, ttl)
+
+	if ttl > 0 {
+		GetLogger(internalModule).Infof("level of %s set to %s for %s by %s",
+			request.Module,
+			*request.Level,
+			ttl,
+			r.RemoteAddr,
+		)
+	} else {
+		GetLogger(internalModule).Infof("level of %s set to %s by %s",
+			request.Module,
+			*request.Level,
+			r.RemoteAddr,
+		)
+	}
+
+	for _, module := range handler.modules() {
+		if module.Module == request.Module {
+			handler.writeJSON(w, http.StatusOK, module)
+			return
+		}
+	}
+}
+
+// Sets the level of a module, reverting it after ttl if it is not zero.
+func (handler *AdminHandler) setLevel(name string, l Level, ttl time.Duration) {
+	handler.lock.Lock()
+	defer handler.lock.Unlock()
+
+	loggersLock.Lock()
+	defer loggersLock.Unlock()
+
+	override, overridden := handler.overrides[name]
+	if overridden {
+		override.cancel()
+		delete(handler.overrides, name)
+	}
+
+	if ttl > 0 {
+		next := &levelOverride{
+			level: l,
+			expires: now().Add(ttl),
+		}
+
+		// a module overridden again reverts to the level it had before the
+		// first override
+		if overridden {
+			next.previous, next.explicit = override.previous, override.explicit
+		} else if log, ok := loggers[name]; ok {
+			next.previous, next.explicit = log.getConfig().Level, log.explicit
+		}
+
+		next.cancel = afterFunc(ttl, func() {
+			handler.expire(name, next)
+		})
+
+		if handler.overrides == nil {
+			handler.overrides = make(map[string]*levelOverride)
+		}
+
+		handler.overrides[name] = next
+	}
+
+	setLevel(name, l)
+}
+
+// Reverts a temporary level once it expires.
+func (handler *AdminHandler) expire(name string, override *levelOverride) {
+	handler.lock.Lock()
+	defer handler.lock.Unlock()
+
+	// the override may have been replaced while the revert was scheduled
+	if handler.overrides[name] != override {
+		return
+	}
+
+	delete(handler.overrides, name)
+
+	loggersLock.Lock()
+	log, ok := loggers[name]
+	reverted := ok && log.getConfig().Level == override.level
+	if reverted {
+		if override.explicit {
+			setLevel(name, override.previous)
+		} else {
+			unsetLevel(name)
+		}
+	}
+	loggersLock.Unlock()
+
+	if reverted {
+		GetLogger(internalModule).Infof("temporary level %s of %s expired", override.level, name)
+	}
+}
+
+// Returns every registered module, sorted by name.
+func (handler *AdminHandler) modules() []adminModule {
+	handler.lock.Lock()
+	defer handler.lock.Unlock()
+
+	loggersLock.RLock()
+	defer loggersLock.RUnlock()
+
+	modules := make([]adminModule, 0, len(loggers))
+	for name, log := range loggers {
+		config := log.getConfig()
+		module := adminModule{
+			Module: name,
+			Level: config.Level,
+			Prefix: config.Prefix,
+			Explicit: log.explicit,
+		}
+
+		if override, ok := handler.overrides[name]; ok {
+			expires := override.expires
+			module.Expires = &expires
+		}
+
+		modules = append(modules, module)
+	}
+
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Module < modules[j].Module
+	})
+
+	return modules
+}
+
+func (handler *AdminHandler) writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(value)
+}
@@ -0,0 +1,211 @@
+package golog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Replaces afterFunc for the duration of the test, returning the reverts
+// scheduled in order; the test calls them to expire a level.
+func mockAfterFunc(t *testing.T) *[]func() {
+	original := afterFunc
+
+	var scheduled []func()
+	afterFunc = func(d time.Duration, f func()) func() bool {
+		scheduled = append(scheduled, f)
+		return func() bool {
+			return true
+		}
+	}
+
+	t.Cleanup(func() {
+		afterFunc = original
+	})
+
+	return &scheduled
+}
+
+func serveAdmin(handler *AdminHandler, method string, body string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(method, "/debug/golog", strings.NewReader(body)))
+
+	return response
+}
+
+func TestAdminHandler_List(t *testing.T) {
+	resetRegistry(t)
+
+	Setup(internalModule, LogConfig{Level: NOLOG})
+	Setup("db", LogConfig{
+		Prefix: "[database] ",
+		Level: WARN,
+	})
+	GetLogger("db.pool")
+
+	response := serveAdmin(&AdminHandler{}, http.MethodGet, "")
+	if response.Code != http.StatusOK {
+		t.Fatalf("Expect status %d, got %d instead", http.StatusOK, response.Code)
+	}
+
+	var modules []adminModule
+	if err := json.Unmarshal(response.Body.Bytes(), &modules); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := []adminModule{
+		{Module: "db", Level: WARN, Prefix: "[database] ", Explicit: true},
+		{Module: "db.pool", Level: WARN, Prefix: "[db.pool] "},
+		{Module: internalModule, Level: NOLOG, Explicit: true},
+	}
+
+	if len(modules) != len(expected) {
+		t.Fatalf("Expect %d modules, got %+v", len(expected), modules)
+	}
+
+	for i, module := range modules {
+		if module != expected[i] {
+			t.Errorf("TC %d: Expect %+v, got %+v instead", i, expected[i], module)
+		}
+	}
+
+	if !strings.Contains(response.Body.String(), `"level": "WARN"`) {
+		t.Errorf("Expect levels to be listed by name, got %s", response.Body)
+	}
+}
+
+func TestAdminHandler_Change(t *testing.T) {
+	resetRegistry(t)
+	mockNow(t)
+	scheduled := mockAfterFunc(t)
+
+	Setup(internalModule, LogConfig{Level: NOLOG})
+	Setup("db", LogConfig{Level: WARN})
+
+	pool := GetLogger("db.pool").(*logger)
+	web := GetLogger("http").(*logger)
+	handler := &AdminHandler{}
+
+	response := serveAdmin(handler, http.MethodPut, `{"module": "db", "level": "INFO"}`)
+	if response.Code != 200 || !strings.Contains(response.Body.String(), `"level": "INFO"`) {
+		t.Fatalf("Expect the changed module in the response, got %d: %s", response.Code, response.Body)
+	}
+
+	response = serveAdmin(handler, http.MethodPost, `{"module": "db.pool", "level": "VERBOSE", "ttl": "15m"}`)
+	if !strings.Contains(response.Body.String(), `"expires": "2017-03-01T10:15:00Z"`) {
+		t.Errorf("Expect the expiry in the response, got %s", response.Body)
+	}
+
+	serveAdmin(handler, http.MethodPost, `{"module": "http", "level": "ERROR", "ttl": "1h"}`)
+
+	checkLoggers(t, []struct {
+		LoggerName string
+		Level Level
+		Prefix string
+	}{
+		{"db", INFO, ""},
+		{"db.pool", VERBOSE, "[db.pool] "},
+		{"http", ERROR, "[http] "},
+	})
+
+	if len(*scheduled) != 2 {
+		t.Fatalf("Expect 2 reverts to be scheduled, got %d", len(*scheduled))
+	}
+
+	// the pool inherited its level before the override, and keeps following
+	// db once it expires
+	(*scheduled)[0]()
+	SetLevel("db", DEBUG)
+	if pool.explicit || pool.getConfig().Level != DEBUG {
+		t.Errorf("Expect the expired module to inherit %s again, got %s",
+			DEBUG,
+			pool.getConfig().Level,
+		)
+	}
+
+	// a level changed by something else in between is kept
+	SetLevel("http", WARN)
+	(*scheduled)[1]()
+	if web.getConfig().Level != WARN {
+		t.Errorf("Expect a level changed since the override to be kept, got %s",
+			web.getConfig().Level,
+		)
+	}
+
+	for _, module := range handler.modules() {
+		if module.Expires != nil {
+			t.Errorf("Expect no temporary level to be left, got %+v", module)
+		}
+	}
+}
+
+func TestAdminHandler_Renew(t *testing.T) {
+	resetRegistry(t)
+	scheduled := mockAfterFunc(t)
+
+	Setup(internalModule, LogConfig{Level: NOLOG})
+	Setup("db", LogConfig{Level: WARN})
+	handler := &AdminHandler{}
+
+	serveAdmin(handler, http.MethodPut, `{"module": "db", "level": "DEBUG", "ttl": "5m"}`)
+	serveAdmin(handler, http.MethodPut, `{"module": "db", "level": "VERBOSE", "ttl": "5m"}`)
+
+	// the first revert was replaced by the second
+	(*scheduled)[0]()
+	if GetLogger("db").(*logger).getConfig().Level != VERBOSE {
+		t.Errorf("Expect a replaced revert to do nothing, got %s",
+			GetLogger("db").(*logger).getConfig().Level,
+		)
+	}
+
+	(*scheduled)[1]()
+	if GetLogger("db").(*logger).getConfig().Level != WARN {
+		t.Errorf("Expect the level before the first override, got %s",
+			GetLogger("db").(*logger).getConfig().Level,
+		)
+	}
+}
+
+func TestAdminHandler_Invalid(t *testing.T) {
+	resetRegistry(t)
+	mockAfterFunc(t)
+
+	Setup(internalModule, LogConfig{Level: NOLOG})
+	handler := &AdminHandler{MaxTTL: time.Hour}
+
+	testCases := []struct {
+		Method string
+		Body string
+		Status int
+		Error string
+	}{
+		{http.MethodDelete, "", http.StatusMethodNotAllowed, "method not allowed"},
+		{http.MethodPut, `{"module": "db"`, http.StatusBadRequest, "invalid request"},
+		{http.MethodPut, `{"module": "db", "level": "LOUD", "ttl": "1m"}`, http.StatusBadRequest, `unknown level "LOUD"`},
+		{http.MethodPut, `{"module": "db", "levle": "INFO", "ttl": "1m"}`, http.StatusBadRequest, `unknown field "levle"`},
+		{http.MethodPut, `{"level": "INFO", "ttl": "1m"}`, http.StatusBadRequest, "module and level are required"},
+		{http.MethodPut, `{"module": "db", "level": "INFO", "ttl": "soon"}`, http.StatusBadRequest, `invalid ttl "soon"`},
+		{http.MethodPut, `{"module": "db", "level": "INFO"}`, http.StatusBadRequest, "ttl must be set and at most 1h0m0s"},
+		{http.MethodPut, `{"module": "db", "level": "INFO", "ttl": "2h"}`, http.StatusBadRequest, "ttl must be set and at most 1h0m0s"},
+	}
+
+	for i, c := range testCases {
+		response := serveAdmin(handler, c.Method, c.Body)
+		if response.Code != c.Status || !strings.Contains(response.Body.String(), c.Error) {
+			t.Errorf("TC %d: Expect %d %q, got %d %q instead",
+				i,
+				c.Status,
+				c.Error,
+				response.Code,
+				response.Body,
+			)
+		}
+	}
+
+	if _, ok := loggers["db"]; ok {
+		t.Error("Expect invalid requests not to change anything.")
+	}
+}
@@ -0,0 +1,41 @@
+package golog
+
+import "time"
+
+// Represents a single log issued through a module logger.
+type Record struct {
+	// the time the log was issued
+	Time time.Time
+
+	// the level of the log; FATAL for Fatal and Fatalf
+	Level Level
+
+	// the name of the module the log was issued for
+	Module string
+
+	// the prefix configured for the module
+	Prefix string
+
+	// the formatted message, without the prefix
+	Message string
+
+	// the key/value pairs attached to the log, in the order they were given
+	Fields []Field
+
+	// whether the process exits once a FATAL record is written, as opposed to
+	// the record being handed over to a FatalHandler
+	exits bool
+}
+
+// Represents where a module's logs are written to, and is configured through
+// LogConfig.Backend. Backends must be safe for concurrent use, since the same
+// module logger can be used from any goroutine.
+type Backend interface {
+	// Writes the record. depth is the number of stack frames between the
+	// caller of Log and the application code that issued the log, as in
+	// glog.InfoDepth, and can be used to report the file:line of the log.
+	//
+	// Backends need not exit on FATAL records; the logger exits once Log
+	// returns.
+	Log(depth int, record Record)
+}
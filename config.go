@@ -0,0 +1,388 @@
+package golog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The outputs and formats of the modules in a configuration file
+const (
+	outputGlog = "glog"
+	outputStderr = "stderr"
+	outputStdout = "stdout"
+
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// Represents the configuration of a module read from a configuration file.
+// Settings that are not in the file are left nil.
+type moduleConfig struct {
+	level *Level
+	prefix *string
+	backend Backend
+
+	// describes the backend, e.g. "stderr/json"; empty if not set
+	output string
+}
+
+// Represents a configuration file, e.g.
+//
+//	default:
+//	  level: INFO
+//	  output: glog
+//	  format: text
+//	modules:
+//	  db:
+//	    level: DEBUG
+//	    prefix: "[database] "
+//	  billing.invoice:
+//	    level: WARN
+//	    output: stderr
+//	    format: json
+//
+// or the equivalent JSON document.
+type fileConfig struct {
+	// the level of the modules that do not have a level of their own
+	defaultLevel *Level
+
+	// the backend of the modules that do not have a backend of their own,
+	// and its description as for moduleConfig
+	defaultBackend Backend
+	defaultOutput string
+
+	modules map[string]moduleConfig
+}
+
+// The settings of a section of the configuration file, before the output
+// and format are turned into a backend.
+type moduleSettings struct {
+	level *Level
+	prefix *string
+
+	output string
+	format string
+
+	// the node reported for an invalid combination of output and format
+	outputNode *yaml.Node
+}
+
+// Collects the problems found while parsing a configuration file.
+type configParser struct {
+	errs ConfigErrors
+}
+
+func (parser *configParser) errorf(node *yaml.Node, message string, args ...interface{}) {
+	parser.errs = append(parser.errs, fmt.Errorf("line %d: " + message, append([]interface{}{node.Line}, args...)...))
+}
+
+// Returns the key/value pairs of a mapping node, reporting any key that is
+// not one of the allowed ones (if given).
+func (parser *configParser) mapping(node *yaml.Node, what string, allowed ...string) [][2]*yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		parser.errorf(node, "%s must be a mapping", what)
+		return nil
+	}
+
+	var pairs [][2]*yaml.Node
+	for i := 0; i + 1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i + 1]
+
+		if len(allowed) > 0 && !contains(allowed, key.Value) {
+			parser.errorf(key, "unknown key %q in %s; expected one of %v", key.Value, what, allowed)
+			continue
+		}
+
+		pairs = append(pairs, [2]*yaml.Node{key, value})
+	}
+
+	return pairs
+}
+
+func (parser *configParser) scalar(node *yaml.Node, what string) (string, bool) {
+	if node.Kind != yaml.ScalarNode {
+		parser.errorf(node, "%s must be a string", what)
+		return "", false
+	}
+
+	return node.Value, true
+}
+
+func (parser *configParser) level(node *yaml.Node, what string) *Level {
+	value, ok := parser.scalar(node, what)
+	if !ok {
+		return nil
+	}
+
+	l, ok := GetLevel(value)
+	if !ok {
+		parser.errorf(node, "unknown level %q for %s", value, what)
+		return nil
+	}
+
+	return &l
+}
+
+// Reads the settings of a module, or of the default section.
+func (parser *configParser) settings(node *yaml.Node, what string) moduleSettings {
+	settings := moduleSettings{}
+
+	for _, pair := range parser.mapping(node, what, "level", "prefix", "output", "format") {
+		key, value := pair[0], pair[1]
+
+		switch key.Value {
+		case "level":
+			settings.level = parser.level(value, what + " level")
+
+		case "prefix":
+			if prefix, ok := parser.scalar(value, what + " prefix"); ok {
+				settings.prefix = &prefix
+			}
+
+		case "output":
+			if output, ok := parser.scalar(value, what + " output"); ok {
+				if !contains([]string{outputGlog, outputStderr, outputStdout}, output) {
+					parser.errorf(value, "unknown output %q for %s; expected glog, stderr or stdout", output, what)
+					continue
+				}
+
+				settings.output = output
+				settings.outputNode = value
+			}
+
+		case "format":
+			if format, ok := parser.scalar(value, what + " format"); ok {
+				if !contains([]string{formatText, formatJSON}, format) {
+					parser.errorf(value, "unknown format %q for %s; expected text or json", format, what)
+					continue
+				}
+
+				settings.format = format
+				settings.outputNode = value
+			}
+		}
+	}
+
+	return settings
+}
+
+// Returns the backend for the output and format along with its description,
+// and false if they cannot be combined.
+func (parser *configParser) backend(settings moduleSettings, what string) (Backend, string, bool) {
+	output, format := settings.output, settings.format
+	if output == "" && format == "" {
+		return nil, "", true
+	}
+
+	if output == "" {
+		output = outputGlog
+	}
+
+	if format == "" {
+		format = formatText
+		if output != outputGlog {
+			format = formatJSON
+		}
+	}
+
+	description := output + "/" + format
+
+	switch {
+	case output == outputGlog && format == formatText:
+		return defaultBackend(), description, true
+
+	case output == outputStderr && format == formatText:
+		return NewTextBackend(os.Stderr, TextFormat{}), description, true
+
+	case output == outputStderr && format == formatJSON:
+		return NewJSONBackend(os.Stderr), description, true
+
+	case output == outputStdout && format == formatText:
+		return NewTextBackend(os.Stdout, TextFormat{}), description, true
+
+	case output == outputStdout && format == formatJSON:
+		return NewJSONBackend(os.Stdout), description, true
+	}
+
+	parser.errorf(settings.outputNode, "%s: format %s cannot be written to output %s", what, format, output)
+	return nil, "", false
+}
+
+// Parses and validates a YAML or JSON configuration document.
+func parseConfig(data []byte) (*fileConfig, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	config := &fileConfig{
+		modules: make(map[string]moduleConfig),
+	}
+
+	// an empty document
+	if len(document.Content) == 0 {
+		return config, nil
+	}
+
+	parser := &configParser{}
+
+	var modules *yaml.Node
+
+	for _, pair := range parser.mapping(document.Content[0], "the configuration", "default", "modules") {
+		switch pair[0].Value {
+		case "default":
+			settings := parser.settings(pair[1], "default")
+			if settings.prefix != nil {
+				parser.errorf(pair[1], "default: prefix cannot be set for every module")
+			}
+
+			config.defaultLevel = settings.level
+			config.defaultBackend, config.defaultOutput, _ = parser.backend(settings, "default")
+
+		case "modules":
+			modules = pair[1]
+		}
+	}
+
+	if modules != nil {
+		for _, pair := range parser.mapping(modules, "modules") {
+			name, ok := parser.scalar(pair[0], "module name")
+			if !ok {
+				continue
+			}
+
+			if name == "" {
+				parser.errorf(pair[0], "module name cannot be empty")
+				continue
+			}
+
+			what := "module " + name
+			settings := parser.settings(pair[1], what)
+			backend, output, _ := parser.backend(settings, what)
+
+			config.modules[name] = moduleConfig{
+				level: settings.level,
+				prefix: settings.prefix,
+				backend: backend,
+				output: output,
+			}
+		}
+	}
+
+	if len(parser.errs) > 0 {
+		return nil, parser.errs
+	}
+
+	return config, nil
+}
+
+// Applies the configuration to the registry; must be called with loggersLock
+// held for writing.
+func applyConfig(config *fileConfig) {
+	if config.defaultLevel != nil {
+		setLevelRule("", *config.defaultLevel)
+	}
+
+	if config.defaultBackend != nil {
+		setBackendRule(config.defaultBackend)
+	}
+
+	// apply ancestors before their descendants, so that the result does not
+	// depend on the order of the map
+	names := make([]string, 0, len(config.modules))
+	for name := range config.modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		module := config.modules[name]
+
+		if module.level != nil {
+			setLevel(name, *module.level)
+		}
+
+		if module.prefix != nil {
+			setPrefix(name, *module.prefix)
+		}
+
+		if module.backend != nil {
+			setBackend(name, module.backend)
+		}
+	}
+}
+
+// Loads a YAML or JSON configuration document into the registry used by
+// GetLogger, e.g.
+//
+//	default:
+//	  level: INFO
+//	modules:
+//	  db:
+//	    level: DEBUG
+//	    prefix: "[database] "
+//	  billing.invoice:
+//	    level: WARN
+//	    output: stderr
+//	    format: json
+//
+// The default level applies to every module that does not have a level of
+// its own, like the spec "*=LEVEL", and the default output and format to
+// every module that has no backend of its own, whether listed in the file or
+// not. Modules keep the other defaults of GetLogger, e.g. the prefix
+// "[$name] ".
+//
+// Outputs are glog (the default), stderr and stdout; formats are text and
+// json, which glog does not support. The format defaults to text for glog,
+// and json for stderr and stdout. In builds with the noglog tag, glog stands
+// for the default backend, text written to stderr.
+//
+// The document is applied only if it is valid; otherwise the returned error
+// lists every problem along with its line number.
+func LoadConfig(reader io.Reader) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	config, err := parseConfig(data)
+	if err != nil {
+		return err
+	}
+
+	loggersLock.Lock()
+	defer loggersLock.Unlock()
+
+	applyConfig(config)
+	return nil
+}
+
+// Loads a YAML or JSON configuration file; same as LoadConfig, except that
+// errors are prefixed with the path of the file.
+func LoadConfigFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := LoadConfig(file); err != nil {
+		return fmt.Errorf("%s: %s", path, err)
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,290 @@
+package golog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Checks the level and prefix of the loggers by name
+func checkLoggers(t *testing.T, testCases []struct {
+	LoggerName string
+	Level Level
+	Prefix string
+}) {
+	t.Helper()
+
+	for i, c := range testCases {
+		config := GetLogger(c.LoggerName).(*logger).getConfig()
+		if config.Level != c.Level {
+			t.Errorf("TC %d: Expected level of %s to be %s, actual: %s",
+				i,
+				c.LoggerName,
+				c.Level,
+				config.Level,
+			)
+		}
+
+		if config.Prefix != c.Prefix {
+			t.Errorf("TC %d: Expected prefix of %s to be %q, actual: %q",
+				i,
+				c.LoggerName,
+				c.Prefix,
+				config.Prefix,
+			)
+		}
+	}
+}
+
+const testYAMLConfig = `
+default:
+  level: warn
+modules:
+  db:
+    level: DEBUG
+    prefix: "[database] "
+  billing:
+    level: ERROR
+  billing.invoice:
+    prefix: "[invoice] "
+    output: stderr
+    format: json
+`
+
+const testJSONConfig = `{
+	"default": {"level": "warn"},
+	"modules": {
+		"db": {"level": "DEBUG", "prefix": "[database] "},
+		"billing": {"level": "ERROR"},
+		"billing.invoice": {"prefix": "[invoice] ", "output": "stderr", "format": "json"}
+	}
+}`
+
+func TestLoadConfig(t *testing.T) {
+	for _, document := range []string{testYAMLConfig, testJSONConfig} {
+		resetRegistry(t)
+
+		db := GetLogger("db").(*logger)
+
+		if err := LoadConfig(strings.NewReader(document)); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		checkLoggers(t, []struct {
+			LoggerName string
+			Level Level
+			Prefix string
+		}{
+			{"db", DEBUG, "[database] "},
+			{"billing", ERROR, "[billing] "},
+			{"billing.invoice", ERROR, "[invoice] "},
+			{"billing.invoice.pdf", ERROR, "[billing.invoice.pdf] "},
+			{"http", WARN, "[http] "},
+		})
+
+		if db.getConfig().Level != DEBUG {
+			t.Error("Expect existing loggers to pick up the configuration.")
+		}
+
+		if db.getConfig().backend() != defaultBackend() {
+			t.Errorf("Expect db to log to glog, got %T", db.getConfig().backend())
+		}
+
+		invoice := GetLogger("billing.invoice").(*logger).getConfig().Backend
+		if backend, ok := invoice.(*JSONBackend); !ok || backend.writer != os.Stderr {
+			t.Errorf("Expect billing.invoice to log JSON to stderr, got %#v", invoice)
+		}
+	}
+}
+
+func TestLoadConfig_DefaultOutput(t *testing.T) {
+	resetRegistry(t)
+
+	err := LoadConfig(strings.NewReader(`
+default:
+  output: stdout
+modules:
+  db:
+    level: INFO
+  http:
+    output: glog
+`))
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	db := GetLogger("db").(*logger).getConfig().backend()
+	if backend, ok := db.(*JSONBackend); !ok || backend.writer != os.Stdout {
+		t.Errorf("Expect db to log JSON to stdout, got %#v", db)
+	}
+
+	if GetLogger("http").(*logger).getConfig().Backend != defaultBackend() {
+		t.Error("Expect http to log to glog.")
+	}
+
+	// modules that are not listed follow the default output as well
+	cache := GetLogger("cache").(*logger).getConfig()
+	if cache.Backend != nil || cache.backend() != db {
+		t.Errorf("Expect cache to log JSON to stdout, got %#v", cache.backend())
+	}
+
+	// unless they have a backend of their own
+	own := &MockBackend{}
+	Setup("billing", LogConfig{Backend: own})
+	if GetLogger("billing").(*logger).getConfig().backend() != own {
+		t.Error("Expect billing to keep its own backend.")
+	}
+}
+
+func TestLoadConfig_TextOutput(t *testing.T) {
+	resetRegistry(t)
+
+	err := LoadConfig(strings.NewReader(`
+modules:
+  db:
+    output: stderr
+    format: text
+  http:
+    output: stdout
+    format: text
+`))
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	testCases := []struct {
+		LoggerName string
+		Writer *os.File
+	}{
+		{"db", os.Stderr},
+		{"http", os.Stdout},
+	}
+
+	for i, c := range testCases {
+		backend := GetLogger(c.LoggerName).(*logger).getConfig().Backend
+		if text, ok := backend.(*TextBackend); !ok || text.writer != c.Writer {
+			t.Errorf("TC %d: Expect %s to log text to %s, got %#v", i, c.LoggerName, c.Writer.Name(), backend)
+		}
+	}
+}
+
+func TestLoadConfig_Errors(t *testing.T) {
+	resetRegistry(t)
+
+	testCases := []struct {
+		Document string
+		Errors []string
+	}{
+		{
+			Document: "modules:\n  db: [\n",
+			Errors: []string{"yaml: line 2"},
+		},
+		{
+			Document: "- db\n",
+			Errors: []string{"line 1: the configuration must be a mapping"},
+		},
+		{
+			Document: "default:\n  level: LOUD\nmodule:\n  db:\n    level: INFO\n",
+			Errors: []string{
+				`line 2: unknown level "LOUD" for default level`,
+				`line 3: unknown key "module" in the configuration`,
+			},
+		},
+		{
+			Document: "modules:\n  db:\n    level: INFO\n    colour: red\n  http:\n    level: [INFO]\n",
+			Errors: []string{
+				`line 4: unknown key "colour" in module db`,
+				`line 6: module http level must be a string`,
+			},
+		},
+		{
+			Document: "modules:\n  db:\n    output: syslog\n  http:\n    format: xml\n",
+			Errors: []string{
+				`line 3: unknown output "syslog" for module db`,
+				`line 5: unknown format "xml" for module http`,
+			},
+		},
+		{
+			Document: "modules:\n  db:\n    output: glog\n    format: json\n  http:\n    format: json\n",
+			Errors: []string{
+				`line 4: module db: format json cannot be written to output glog`,
+				`line 6: module http: format json cannot be written to output glog`,
+			},
+		},
+		{
+			Document: "default:\n  prefix: \"[x] \"\nmodules: db\n",
+			Errors: []string{
+				`line 2: default: prefix cannot be set for every module`,
+				`line 3: modules must be a mapping`,
+			},
+		},
+	}
+
+	for i, c := range testCases {
+		err := LoadConfig(strings.NewReader(c.Document))
+		if err == nil {
+			t.Errorf("TC %d: Expected an error", i)
+			continue
+		}
+
+		for _, message := range c.Errors {
+			if !strings.Contains(err.Error(), message) {
+				t.Errorf("TC %d: Expected error to contain %q, actual: %q",
+					i,
+					message,
+					err.Error(),
+				)
+			}
+		}
+
+		if errs, ok := err.(ConfigErrors); ok && len(errs) != len(c.Errors) {
+			t.Errorf("TC %d: Expected %d errors, actual: %q", i, len(c.Errors), err.Error())
+		}
+	}
+
+	// nothing is applied unless the whole document is valid
+	if len(loggers) != 0 || len(levelRules) != 0 {
+		t.Errorf("Expected nothing to be configured, got %d loggers", len(loggers))
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	resetRegistry(t)
+
+	path := filepath.Join(t.TempDir(), "logging.yaml")
+	if err := os.WriteFile(path, []byte(testYAMLConfig), 0644); err != nil {
+		t.Fatalf("Unable to write config: %s", err)
+	}
+
+	if err := LoadConfigFile(path); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if GetLogger("db").(*logger).getConfig().Level != DEBUG {
+		t.Error("Expect db to be set to DEBUG.")
+	}
+
+	if err := os.WriteFile(path, []byte("default:\n  level: LOUD\n"), 0644); err != nil {
+		t.Fatalf("Unable to write config: %s", err)
+	}
+
+	err := LoadConfigFile(path)
+	if err == nil || !strings.HasPrefix(err.Error(), path + ": line 2: unknown level") {
+		t.Errorf("Expected error prefixed with the path and line, got %v", err)
+	}
+
+	if err := LoadConfigFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected an error for a missing file.")
+	}
+}
+
+func TestLoadConfig_Empty(t *testing.T) {
+	resetRegistry(t)
+
+	if err := LoadConfig(strings.NewReader("")); err != nil {
+		t.Errorf("Unexpected error for an empty document: %s", err)
+	}
+}
@@ -0,0 +1,115 @@
+package golog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// The environment variables read by SetupFromEnv
+const (
+	envPrefix = "GOLOG_"
+	envLevel = "GOLOG_LEVEL"
+	envModuleLevel = "GOLOG_LEVEL_"
+	envModulePrefix = "GOLOG_PREFIX_"
+)
+
+// Returns the module name for the suffix of an environment variable, e.g.
+// "billing.my-module" for "BILLING__MY_MODULE".
+func envModuleName(suffix string) string {
+	name := strings.ToLower(suffix)
+	name = strings.Replace(name, "__", ".", -1)
+	return strings.Replace(name, "_", "-", -1)
+}
+
+// Configures the module loggers from environment variables:
+//
+//	GOLOG_LEVEL sets the level of every module that does not have a level
+//	of its own, like the spec "*=LEVEL"
+//	GOLOG_LEVEL_<MODULE> sets the level of the module, as SetLevel does
+//	GOLOG_PREFIX_<MODULE> sets the prefix of the module
+//
+// <MODULE> is the module name in upper case, with "." written as "__" and
+// "-" as "_", e.g. GOLOG_LEVEL_BILLING__MY_MODULE for "billing.my-module".
+//
+// Any other variable starting with GOLOG_ is an error, so that a typo such as
+// GOLOG_LEVLE_DB is not silently ignored. The variables are applied only if
+// all of them are valid; otherwise the returned ConfigErrors lists every
+// malformed variable.
+func SetupFromEnv() error {
+	return setupFromEnv(os.Environ())
+}
+
+// Same as SetupFromEnv, reading from a list of "key=value" strings as
+// returned by os.Environ.
+func setupFromEnv(environ []string) error {
+	var errs ConfigErrors
+	var rules []specEntry
+	prefixes := make(map[string]string)
+
+	for _, variable := range environ {
+		i := strings.Index(variable, "=")
+		if i < 0 {
+			continue
+		}
+
+		key, value := variable[:i], variable[i + 1:]
+
+		switch {
+		case key == envLevel:
+			l, ok := GetLevel(value)
+			if !ok {
+				errs = append(errs, fmt.Errorf("golog: %s: unknown level %q", key, value))
+				continue
+			}
+
+			rules = append(rules, specEntry{pattern: "*", level: l})
+
+		case strings.HasPrefix(key, envModuleLevel):
+			name := envModuleName(strings.TrimPrefix(key, envModuleLevel))
+			if name == "" {
+				errs = append(errs, fmt.Errorf("golog: %s: missing module name", key))
+				continue
+			}
+
+			l, ok := GetLevel(value)
+			if !ok {
+				errs = append(errs, fmt.Errorf("golog: %s: unknown level %q", key, value))
+				continue
+			}
+
+			rules = append(rules, specEntry{pattern: name, level: l})
+
+		case strings.HasPrefix(key, envModulePrefix):
+			name := envModuleName(strings.TrimPrefix(key, envModulePrefix))
+			if name == "" {
+				errs = append(errs, fmt.Errorf("golog: %s: missing module name", key))
+				continue
+			}
+
+			prefixes[name] = value
+
+		case strings.HasPrefix(key, envPrefix):
+			errs = append(errs, fmt.Errorf("golog: %s: unknown variable; expected %s, %s<MODULE> or %s<MODULE>",
+				key,
+				envLevel,
+				envModuleLevel,
+				envModulePrefix,
+			))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	loggersLock.Lock()
+	defer loggersLock.Unlock()
+
+	applySpec(rules)
+	for name, prefix := range prefixes {
+		setPrefix(name, prefix)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package golog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnvModuleName(t *testing.T) {
+	testCases := []struct {
+		Suffix string
+		Name string
+	}{
+		{"DB", "db"},
+		{"MY_MODULE", "my-module"},
+		{"BILLING__INVOICE__PDF", "billing.invoice.pdf"},
+		{"BILLING__MY_MODULE", "billing.my-module"},
+		{"", ""},
+	}
+
+	for i, c := range testCases {
+		if name := envModuleName(c.Suffix); name != c.Name {
+			t.Errorf("TC %d: Expected module name %s, actual: %s",
+				i,
+				c.Name,
+				name,
+			)
+		}
+	}
+}
+
+func TestSetupFromEnv(t *testing.T) {
+	resetRegistry(t)
+
+	db := GetLogger("db").(*logger)
+
+	err := setupFromEnv([]string{
+		"HOME=/root",
+		"GOLOG_LEVEL=warn",
+		"GOLOG_LEVEL_DB=DEBUG",
+		"GOLOG_LEVEL_BILLING__INVOICE=ERROR",
+		"GOLOG_PREFIX_BILLING__INVOICE=[invoice] ",
+		"GOLOG_PREFIX_MY_MODULE=[mine] ",
+	})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	testCases := []struct {
+		LoggerName string
+		Level Level
+		Prefix string
+	}{
+		{"db", DEBUG, "[db] "},
+		{"billing.invoice", ERROR, "[invoice] "},
+		{"billing.invoice.pdf", ERROR, "[billing.invoice.pdf] "},
+		{"my-module", WARN, "[mine] "},
+		{"http", WARN, "[http] "},
+	}
+
+	for i, c := range testCases {
+		config := GetLogger(c.LoggerName).(*logger).getConfig()
+		if config.Level != c.Level {
+			t.Errorf("TC %d: Expected level of %s to be %s, actual: %s",
+				i,
+				c.LoggerName,
+				c.Level,
+				config.Level,
+			)
+		}
+
+		if config.Prefix != c.Prefix {
+			t.Errorf("TC %d: Expected prefix of %s to be %q, actual: %q",
+				i,
+				c.LoggerName,
+				c.Prefix,
+				config.Prefix,
+			)
+		}
+	}
+
+	if db.getConfig().Level != DEBUG {
+		t.Error("Expect existing loggers to pick up the level from the environment.")
+	}
+}
+
+func TestSetupFromEnv_Errors(t *testing.T) {
+	resetRegistry(t)
+
+	err := setupFromEnv([]string{
+		"GOLOG_LEVEL=LOUD",
+		"GOLOG_LEVEL_DB=DEBUG",
+		"GOLOG_LEVEL_HTTP=",
+		"GOLOG_LEVEL_=INFO",
+		"GOLOG_PREFIX_=[x]",
+		"GOLOG_LEVLE_DB=DEBUG",
+		"GOLOG_PREFIX=[x]",
+		"GOPATH=/go",
+	})
+
+	errs, ok := err.(ConfigErrors)
+	if !ok {
+		t.Fatalf("Expected ConfigErrors, got %v", err)
+	}
+
+	expected := []string{
+		`GOLOG_LEVEL: unknown level "LOUD"`,
+		`GOLOG_LEVEL_HTTP: unknown level ""`,
+		`GOLOG_LEVEL_: missing module name`,
+		`GOLOG_PREFIX_: missing module name`,
+		`GOLOG_LEVLE_DB: unknown variable`,
+		`GOLOG_PREFIX: unknown variable`,
+	}
+
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got %d: %s", len(expected), len(errs), err)
+	}
+
+	for i, message := range expected {
+		if !strings.Contains(errs[i].Error(), message) {
+			t.Errorf("TC %d: Expected error to contain %q, actual: %q",
+				i,
+				message,
+				errs[i].Error(),
+			)
+		}
+
+		if !strings.Contains(err.Error(), message) {
+			t.Errorf("TC %d: Expected combined error to contain %q", i, message)
+		}
+	}
+
+	// nothing is applied unless every variable is valid
+	if len(loggers) != 0 {
+		t.Errorf("Expected no logger to be configured, got %d", len(loggers))
+	}
+}
+
+func TestSetupFromEnv_Environ(t *testing.T) {
+	resetRegistry(t)
+
+	t.Setenv("GOLOG_LEVEL_DB", "VERBOSE")
+	if err := SetupFromEnv(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if GetLogger("db").(*logger).getConfig().Level != VERBOSE {
+		t.Error("Expect the db logger to be set to VERBOSE.")
+	}
+}
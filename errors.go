@@ -0,0 +1,59 @@
+package golog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Represents every problem found in a configuration source, e.g. each
+// malformed environment variable, so that they can all be fixed at once.
+type ConfigErrors []error
+
+func (errs ConfigErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Describes a fatal message, handed over to the FatalHandler of the logger
+// (see LogConfig) in place of exiting the process.
+type FatalError struct {
+	// the name of the module the message was logged to
+	Module string
+
+	Message string
+
+	// the fields of the log, including the ones attached through With
+	Fields []Field
+}
+
+func (err *FatalError) Error() string {
+	return fmt.Sprintf("%s: %s", err.Module, err.Message)
+}
+
+// The value Panic, Panicf and Panicw panic with, once the message has been
+// logged.
+type PanicError struct {
+	// the name of the module the message was logged to
+	Module string
+
+	Message string
+
+	// the fields of the log, including the ones attached through With
+	Fields []Field
+}
+
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("%s: %s", err.Module, err.Message)
+}
+
+func panicWith(module string, message string, fields []Field) {
+	panic(&PanicError{
+		Module: module,
+		Message: message,
+		Fields: fields,
+	})
+}
@@ -0,0 +1,75 @@
+package golog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Represents a key/value pair attached to a log, e.g. through With or Infow.
+type Field struct {
+	Key string
+	Value interface{}
+}
+
+// The key given to a value that is left without one, e.g. the last value of
+// an odd number of keys and values.
+const missingKey = "!MISSING"
+
+// Appends alternating keys and values to a copy of fields. A Field can be
+// given in place of a key and a value. Keys that are not strings are
+// printed with fmt.Sprint.
+func appendFields(fields []Field, keysAndValues []interface{}) []Field {
+	if len(keysAndValues) == 0 {
+		return fields
+	}
+
+	result := make([]Field, len(fields), len(fields) + (len(keysAndValues) + 1) / 2)
+	copy(result, fields)
+
+	for i := 0; i < len(keysAndValues); i++ {
+		if field, ok := keysAndValues[i].(Field); ok {
+			result = append(result, field)
+			continue
+		}
+
+		if i == len(keysAndValues) - 1 {
+			result = append(result, Field{Key: missingKey, Value: keysAndValues[i]})
+			break
+		}
+
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+
+		result = append(result, Field{Key: key, Value: keysAndValues[i + 1]})
+		i++
+	}
+
+	return result
+}
+
+// Formats the fields as space separated key=value pairs. Values that would
+// be ambiguous, i.e. that are empty or contain spaces, quotes or "=", are
+// quoted.
+func formatFields(fields []Field) string {
+	var builder strings.Builder
+
+	for i, field := range fields {
+		if i > 0 {
+			builder.WriteByte(' ')
+		}
+
+		value := fmt.Sprint(field.Value)
+		if value == "" || strings.ContainsAny(value, " \t\r\n\"=") {
+			value = strconv.Quote(value)
+		}
+
+		builder.WriteString(field.Key)
+		builder.WriteByte('=')
+		builder.WriteString(value)
+	}
+
+	return builder.String()
+}
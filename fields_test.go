@@ -0,0 +1,87 @@
+package golog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAppendFields(t *testing.T) {
+	base := []Field{{"module", "billing"}}
+
+	testCases := []struct {
+		KeysAndValues []interface{}
+		Fields []Field
+	}{
+		{
+			KeysAndValues: nil,
+			Fields: []Field{{"module", "billing"}},
+		},
+		{
+			KeysAndValues: []interface{}{"request_id", 42},
+			Fields: []Field{{"module", "billing"}, {"request_id", 42}},
+		},
+		{
+			KeysAndValues: []interface{}{Field{"a", 1}, "b", true},
+			Fields: []Field{{"module", "billing"}, {"a", 1}, {"b", true}},
+		},
+		{
+			KeysAndValues: []interface{}{1, "one"},
+			Fields: []Field{{"module", "billing"}, {"1", "one"}},
+		},
+		{
+			KeysAndValues: []interface{}{"a", 1, "dangling"},
+			Fields: []Field{{"module", "billing"}, {"a", 1}, {missingKey, "dangling"}},
+		},
+	}
+
+	for i, c := range testCases {
+		fields := appendFields(base, c.KeysAndValues)
+		if !reflect.DeepEqual(fields, c.Fields) {
+			t.Errorf("TC %d: Expected fields %v, actual fields %v",
+				i,
+				c.Fields,
+				fields,
+			)
+		}
+	}
+
+	// the original fields must not be modified
+	if !reflect.DeepEqual(base, []Field{{"module", "billing"}}) {
+		t.Errorf("Expected base fields to be unchanged, got %v", base)
+	}
+}
+
+func TestFormatFields(t *testing.T) {
+	testCases := []struct {
+		Fields []Field
+		Output string
+	}{
+		{
+			Fields: []Field{},
+			Output: "",
+		},
+		{
+			Fields: []Field{{"request_id", 42}, {"ok", true}},
+			Output: "request_id=42 ok=true",
+		},
+		{
+			Fields: []Field{{"name", "John Smith"}, {"empty", ""}},
+			Output: `name="John Smith" empty=""`,
+		},
+		{
+			Fields: []Field{{"quote", `say "hi"`}, {"eq", "a=b"}, {"nl", "a\nb"}},
+			Output: `quote="say \"hi\"" eq="a=b" nl="a\nb"`,
+		},
+	}
+
+	for i, c := range testCases {
+		output := formatFields(c.Fields)
+		if output != c.Output {
+			t.Errorf("TC %d: Expected output %s, actual output %s",
+				i,
+				c.Output,
+				output,
+			)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+//go:build !noglog
+
+package golog
+
+import (
+	"github.com/golang/glog"
+	"runtime"
+)
+
+// The largest dump of goroutine stacks written along with a fatal log
+const maxStacksSize = 64 << 20
+
+// Writes a fatal log to glog, which exits the process; abstracted out for unit
+// testing.
+var glogFatal = glog.FatalDepth
+
+// The default Backend, writing records to glog with the prefix and the fields
+// (as key=value pairs) prepended to the message. ERROR and WARN records are
+// logged with the matching glog severity, everything else as info.
+//
+// Since glog has no severity below info, DEBUG and VERBOSE records are tagged
+// after the prefix to tell them apart from INFO ones, e.g.
+//
+//	I0301 10:00:00.000000 1234 db.go:42] [db] [DEBUG] opened connection
+//
+// FATAL records are logged with glog's fatal severity, followed by the stacks
+// of every goroutine, after which glog exits the process. Since the logger
+// must not exit when the module has a FatalHandler, those FATAL records are
+// logged as errors tagged "[FATAL] " instead.
+//
+// Not available when built with the noglog tag.
+type GlogBackend struct{}
+
+// Returns the tag written after the prefix for levels that glog does not log
+// with a severity of their own.
+func glogTag(l Level) string {
+	switch l {
+	case FATAL:
+		return "[FATAL] "
+
+	case DEBUG:
+		return "[DEBUG] "
+
+	case VERBOSE:
+		return "[VERBOSE] "
+	}
+
+	return ""
+}
+
+func (GlogBackend) Log(depth int, record Record) {
+	tag := glogTag(record.Level)
+	if record.exits {
+		tag = ""
+	}
+
+	message := record.Prefix + tag
+	if len(record.Fields) > 0 {
+		message += formatFields(record.Fields) + " "
+	}
+
+	message += record.Message
+
+	switch record.Level {
+	case FATAL:
+		if record.exits {
+			glogFatal(depth + 1, message + "\n\n" + goroutineStacks())
+			return
+		}
+
+		glog.ErrorDepth(depth + 1, message)
+
+	case ERROR:
+		glog.ErrorDepth(depth + 1, message)
+
+	case WARN:
+		glog.WarningDepth(depth + 1, message)
+
+	default:
+		glog.InfoDepth(depth + 1, message)
+	}
+}
+
+// Returns the stacks of every goroutine, as glog.Fatal used to dump them.
+func goroutineStacks() string {
+	buffer := make([]byte, 1 << 16)
+	for {
+		n := runtime.Stack(buffer, true)
+		if n < len(buffer) || len(buffer) >= maxStacksSize {
+			return string(buffer[:n])
+		}
+
+		buffer = make([]byte, 2 * len(buffer))
+	}
+}
+
+// Writes out the logs glog buffers.
+func (GlogBackend) Flush() error {
+	glog.Flush()
+	return nil
+}
+
+var _ Backend = GlogBackend{}
+var _ Flusher = GlogBackend{}
+
+// Returns the backend of the modules that have none configured: glog, unless
+// built with the noglog tag.
+func defaultBackend() Backend {
+	return GlogBackend{}
+}
@@ -0,0 +1,177 @@
+//go:build !noglog
+
+package golog
+
+import (
+	"flag"
+	"fmt"
+	"github.com/golang/glog"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Runs fn with glog writing to stderr, and returns what was written.
+func captureGlog(t *testing.T, fn func()) string {
+	if err := flag.Set("logtostderr", "true"); err != nil {
+		t.Fatalf("Unable to log to stderr: %s", err)
+	}
+
+	defer flag.Set("logtostderr", "false")
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Unable to create pipe: %s", err)
+	}
+
+	stderr := os.Stderr
+	os.Stderr = writer
+	defer func() {
+		os.Stderr = stderr
+	}()
+
+	fn()
+	writer.Close()
+
+	output, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Unable to read glog output: %s", err)
+	}
+
+	return string(output)
+}
+
+func TestGlogBackend(t *testing.T) {
+	testCases := []struct {
+		Severity string
+		Message string
+		Call func(log Logger)
+	}{
+		{"E", "[FATAL] hello", func(log Logger) { log.Fatalf("%s", "hello") }},
+		{"E", "hello", func(log Logger) { log.Errorf("%s", "hello") }},
+		{"W", "hello", func(log Logger) { log.Warnf("%s", "hello") }},
+		{"I", "hello", func(log Logger) { log.Infof("%s", "hello") }},
+		{"I", "[DEBUG] hello", func(log Logger) { log.Debugf("%s", "hello") }},
+		{"I", "[VERBOSE] hello", func(log Logger) { log.Verbosef("%s", "hello") }},
+	}
+
+	// fatal logs are written as errors when the logger has a handler
+	log := newLogger("TestGlogBackend", LogConfig{
+		Level: VERBOSE,
+		Prefix: "[TestGlogBackend] ",
+		OnFatal: func(err *FatalError) {},
+	})
+
+	for i, c := range testCases {
+		output := captureGlog(t, func() {
+			c.Call(log)
+		})
+
+		// the call site is the function literal in the test case
+		fn := runtime.FuncForPC(reflect.ValueOf(c.Call).Pointer())
+		file, line := fn.FileLine(fn.Entry())
+		location := filepath.Base(file) + ":" + strconv.Itoa(line) + "]"
+
+		if !strings.HasPrefix(output, c.Severity) {
+			t.Errorf("TC %d: Expect severity %s, got %q instead",
+				i,
+				c.Severity,
+				output,
+			)
+		}
+
+		if !strings.Contains(output, location + " [TestGlogBackend] " + c.Message + "\n") {
+			t.Errorf("TC %d: Expect %q to be logged at %s",
+				i,
+				output,
+				location,
+			)
+		}
+	}
+}
+
+func TestGlogBackend_Fatal(t *testing.T) {
+	exitCode := mockExit(t)
+
+	// glog exits on fatal logs, so the test records them instead
+	var message, location string
+	glogFatal = func(depth int, args ...interface{}) {
+		if _, file, line, ok := runtime.Caller(depth + 1); ok {
+			location = filepath.Base(file) + ":" + strconv.Itoa(line)
+		}
+
+		message = fmt.Sprint(args...)
+	}
+	defer func() {
+		glogFatal = glog.FatalDepth
+	}()
+
+	log := newLogger("TestGlogBackend", LogConfig{
+		Level: INFO,
+		Prefix: "[TestGlogBackend] ",
+	})
+
+	_, file, line, _ := runtime.Caller(0)
+	log.Fatalw("lost connection", "attempt", 3)
+
+	expected := filepath.Base(file) + ":" + strconv.Itoa(line + 1)
+	if location != expected {
+		t.Errorf("Expect the fatal log to be issued at %s, got %s instead", expected, location)
+	}
+
+	if !strings.HasPrefix(message, "[TestGlogBackend] attempt=3 lost connection\n\n") {
+		t.Errorf("Expect the fatal message without a tag, got %q instead", message)
+	}
+
+	// the stacks of every goroutine follow the message
+	if !strings.Contains(message, "goroutine ") || !strings.Contains(message, "TestGlogBackend_Fatal") {
+		t.Errorf("Expect the goroutine stacks after the message, got %q instead", message)
+	}
+
+	if *exitCode != 255 {
+		t.Errorf("Expect the logger to exit with code 255, got %d instead", *exitCode)
+	}
+}
+
+func TestGlogBackend_Fields(t *testing.T) {
+	log := newLogger("TestGlogBackend", LogConfig{
+		Level: INFO,
+		Prefix: "[TestGlogBackend] ",
+	})
+
+	output := captureGlog(t, func() {
+		log.With("request_id", 42).Infow("charged card", "user", "John Smith")
+	})
+
+	expected := `] [TestGlogBackend] request_id=42 user="John Smith" charged card` + "\n"
+	if !strings.HasSuffix(output, expected) {
+		t.Errorf("Expect %q to end with %q", output, expected)
+	}
+}
+
+func TestGlogBackend_DebugFields(t *testing.T) {
+	log := newLogger("TestGlogBackend", LogConfig{
+		Level: VERBOSE,
+		Prefix: "[TestGlogBackend] ",
+	})
+
+	output := captureGlog(t, func() {
+		log.Debugw("opened connection", "pool", 3)
+	})
+
+	expected := `] [TestGlogBackend] [DEBUG] pool=3 opened connection` + "\n"
+	if !strings.HasSuffix(output, expected) {
+		t.Errorf("Expect %q to end with %q", output, expected)
+	}
+}
+
+func TestDefaultBackend(t *testing.T) {
+	if _, ok := defaultBackend().(GlogBackend); !ok {
+		t.Errorf("Expect the default backend to be GlogBackend, got %T instead", defaultBackend())
+	}
+}
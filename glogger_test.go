@@ -1,97 +1,79 @@
 package golog
 
 import (
+	"fmt"
 	"testing"
-	"github.com/golang/glog"
+	"os"
 	"reflect"
+	"runtime"
+	"time"
 )
 
 
-func isSameArrays(a, b []interface{}) bool {
-	if len(a) != len(b) {
-		return false
-	}
-
-	for i := range a {
-		if a[i] != b[i] {
-			return false
-		}
-	}
+// A Backend that keeps the records in memory
+type MockBackend struct {
+	Records []Record
 
-	return true
+	// the file:line the last record would be attributed to
+	File string
+	Line int
 }
 
-
-type MockLogger struct {
-	Message string
-	Args []interface{}
-	CallType string
+func (backend *MockBackend) Log(depth int, record Record) {
+	// resolve the location the same way glog's *Depth functions do
+	_, backend.File, backend.Line, _ = runtime.Caller(depth + 1)
+	backend.Records = append(backend.Records, record)
 }
 
-func (logger *MockLogger) Fatal(args ...interface{}) {
-	logger.CallType = "Fatal"
-	logger.Args = args
-}
+// Returns the last record logged, or the zero record if nothing was logged.
+func (backend *MockBackend) Last() Record {
+	if len(backend.Records) == 0 {
+		return Record{}
+	}
 
-func (logger *MockLogger) Fatalf(message string, args ...interface{}) {
-	logger.CallType = "Fatalf"
-	logger.Message = message
-	logger.Args = args
+	return backend.Records[len(backend.Records) - 1]
 }
 
-func (logger *MockLogger) Error(args ...interface{}) {
-	logger.CallType = "Error"
-	logger.Args = args
-}
+func newLoggerWithMocks(config LogConfig) (*logger, *MockBackend) {
+	mockBackend := &MockBackend{}
+	config.Backend = mockBackend
 
-func (logger *MockLogger) Errorf(message string, args ...interface{}) {
-	logger.CallType = "Errorf"
-	logger.Message = message
-	logger.Args = args
+	return newLogger("test", config), mockBackend
 }
 
-func (logger *MockLogger) Warn(args ...interface{}) {
-	logger.CallType = "Warn"
-	logger.Args = args
-}
+// Replaces the exit function for the duration of the test, returning a
+// pointer to the last exit code.
+func mockExit(t *testing.T) *int {
+	code := -1
+	exit = func(c int) {
+		code = c
+	}
 
-func (logger *MockLogger) Warnf(message string, args ...interface{}) {
-	logger.CallType = "Warnf"
-	logger.Message = message
-	logger.Args = args
-}
+	t.Cleanup(func() {
+		exit = os.Exit
+	})
 
-func (logger *MockLogger) Info(args ...interface{}) {
-	logger.CallType = "Info"
-	logger.Args = args
+	return &code
 }
 
-func (logger *MockLogger) Infof(message string, args ...interface{}) {
-	logger.CallType = "Infof"
-	logger.Message = message
-	logger.Args = args
-}
+// The time of every log when using mockNow
+var testTime = time.Date(2017, 3, 1, 10, 0, 0, 0, time.UTC)
+
+// Fixes the time of the logs for the duration of the test.
+func mockNow(t *testing.T) {
+	now = func() time.Time {
+		return testTime
+	}
 
-func newLoggerWithMocks(config LogConfig) (*logger, *MockLogger) {
-	mockLogger := MockLogger{
-		Args: make([]interface{}, 0, 0),
-	}
-
-	return &logger {
-		config: config,
-		fatal: mockLogger.Fatal,
-		fatalf: mockLogger.Fatalf,
-		error: mockLogger.Error,
-		errorf: mockLogger.Errorf,
-		warn: mockLogger.Warn,
-		warnf: mockLogger.Warnf,
-		info: mockLogger.Info,
-		infof: mockLogger.Infof,
-	}, &mockLogger
+	t.Cleanup(func() {
+		now = time.Now
+	})
 }
 
 
 func TestLogger_Fatal(t *testing.T) {
+	exitCode := mockExit(t)
+
 	testCases := []struct {
 		Config LogConfig
 		Messages []interface{}
@@ -142,37 +124,44 @@ func TestLogger_Fatal(t *testing.T) {
 	}
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c.Config)
+		log, mockBackend := newLoggerWithMocks(c.Config)
 		log.Fatal(c.Messages...)
 
-		if mockLogger.CallType != "Fatal" {
-			t.Errorf("TC %d: Expect the call type to be \"Fatal\", got \"%s\" instead",
+		record := mockBackend.Last()
+		if record.Level != FATAL {
+			t.Errorf("TC %d: Expect the record level to be %s, got %s instead",
 				i,
-				mockLogger.CallType,
+				FATAL,
+				record.Level,
 			)
 		}
 
-		if actualPrefix, ok := mockLogger.Args[0].(string); !ok {
-			t.Errorf("TC %d: The first argument passed to the method should be string!",
-				i,
-			)
-		} else if actualPrefix != c.Config.Prefix {
+		if record.Prefix != c.Config.Prefix {
 			t.Errorf("TC %d: Expects prefix %s, received %s instead",
 				i,
 				c.Config.Prefix,
-				actualPrefix,
+				record.Prefix,
 			)
 		}
 
-		if !isSameArrays(c.Messages, mockLogger.Args[1:]) {
+		if record.Message != fmt.Sprint(c.Messages...) {
 			t.Errorf("TC %d: The inputs are not the same!",
 				i,
 			)
 		}
+
+		if *exitCode != 255 {
+			t.Errorf("TC %d: Expect to exit with code 255, got %d instead",
+				i,
+				*exitCode,
+			)
+		}
 	}
 }
 
 func TestLogger_Fatalf(t *testing.T) {
+	exitCode := mockExit(t)
+
 	testCases := []struct {
 		Config LogConfig
 		Messages []interface{}
@@ -211,28 +200,39 @@ func TestLogger_Fatalf(t *testing.T) {
 	}
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c.Config)
+		log, mockBackend := newLoggerWithMocks(c.Config)
 		log.Fatalf(c.Template, c.Messages...)
 
-		if mockLogger.CallType != "Fatalf" {
-			t.Errorf("Test case %d: Expect the call type to be \"Fatalf\", got \"%s\" instead",
+		record := mockBackend.Last()
+		if record.Level != FATAL {
+			t.Errorf("Test case %d: Expect the record level to be %s, got %s instead",
 				i,
-				mockLogger.CallType,
+				FATAL,
+				record.Level,
 			)
 		}
 
-		expectedTemplate := c.Config.Prefix + c.Template
-		if expectedTemplate != mockLogger.Message {
-			t.Errorf("TC %d: The templating message are not the same! Expect %s, Actual %s",
+		if record.Prefix != c.Config.Prefix {
+			t.Errorf("TC %d: Expects prefix %s, received %s instead",
 				i,
-				expectedTemplate,
-				mockLogger.Message,
+				c.Config.Prefix,
+				record.Prefix,
 			)
 		}
 
-		if !isSameArrays(c.Messages, mockLogger.Args) {
-			t.Errorf("TC %d: The inputs are not the same!",
+		expectedMessage := fmt.Sprintf(c.Template, c.Messages...)
+		if expectedMessage != record.Message {
+			t.Errorf("TC %d: The templated messages are not the same! Expect %s, Actual %s",
+				i,
+				expectedMessage,
+				record.Message,
+			)
+		}
+
+		if *exitCode != 255 {
+			t.Errorf("TC %d: Expect to exit with code 255, got %d instead",
 				i,
+				*exitCode,
 			)
 		}
 	}
@@ -245,14 +245,10 @@ func TestLogger_SkipError(t *testing.T) {
 		Prefix: "[TestLogger]",
 	}
 
-	log, mockLogger := newLoggerWithMocks(logConfig)
+	log, mockBackend := newLoggerWithMocks(logConfig)
 	log.Error("hello", "goodbye")
 
-	if mockLogger.CallType != "" {
-		t.Error("Expect Error to not be called.")
-	}
-
-	if len(mockLogger.Args) != 0 {
+	if len(mockBackend.Records) != 0 {
 		t.Error("Expect Error to not be called.")
 	}
 }
@@ -300,29 +296,27 @@ func TestLogger_Error(t *testing.T) {
 	}
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c.Config)
+		log, mockBackend := newLoggerWithMocks(c.Config)
 		log.Error(c.Messages...)
 
-		if mockLogger.CallType != "Error" {
-			t.Errorf("TC %d: Expect the call type to be \"Error\", got \"%s\" instead",
+		record := mockBackend.Last()
+		if record.Level != ERROR {
+			t.Errorf("TC %d: Expect the record level to be %s, got %s instead",
 				i,
-				mockLogger.CallType,
+				ERROR,
+				record.Level,
 			)
 		}
 
-		if actualPrefix, ok := mockLogger.Args[0].(string); !ok {
-			t.Errorf("TC %d: The first argument passed to the method should be string!",
-				i,
-			)
-		} else if actualPrefix != c.Config.Prefix {
+		if record.Prefix != c.Config.Prefix {
 			t.Errorf("TC %d: Expects prefix %s, received %s instead",
 				i,
 				c.Config.Prefix,
-				actualPrefix,
+				record.Prefix,
 			)
 		}
 
-		if !isSameArrays(c.Messages, mockLogger.Args[1:]) {
+		if record.Message != fmt.Sprint(c.Messages...) {
 			t.Errorf("TC %d: The inputs are not the same!",
 				i,
 			)
@@ -336,14 +330,10 @@ func TestLogger_SkipErrorf(t *testing.T) {
 		Prefix: "[TestLogger]",
 	}
 
-	log, mockLogger := newLoggerWithMocks(logConfig)
+	log, mockBackend := newLoggerWithMocks(logConfig)
 	log.Errorf("%s: %s", "hello", "goodbye")
 
-	if mockLogger.CallType != "" {
-		t.Error("Expect Error to not be called.")
-	}
-
-	if len(mockLogger.Args) != 0 {
+	if len(mockBackend.Records) != 0 {
 		t.Error("Expect Error to not be called.")
 	}
 }
@@ -377,28 +367,32 @@ func TestLogger_Errorf(t *testing.T) {
 	}
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c.Config)
+		log, mockBackend := newLoggerWithMocks(c.Config)
 		log.Errorf(c.Template, c.Messages...)
 
-		if mockLogger.CallType != "Errorf" {
-			t.Errorf("Test case %d: Expect the call type to be \"Errorf\", got \"%s\" instead",
+		record := mockBackend.Last()
+		if record.Level != ERROR {
+			t.Errorf("Test case %d: Expect the record level to be %s, got %s instead",
 				i,
-				mockLogger.CallType,
+				ERROR,
+				record.Level,
 			)
 		}
 
-		expectedTemplate := c.Config.Prefix + c.Template
-		if expectedTemplate != mockLogger.Message {
-			t.Errorf("TC %d: The templating message are not the same! Expect %s, Actual %s",
+		if record.Prefix != c.Config.Prefix {
+			t.Errorf("TC %d: Expects prefix %s, received %s instead",
 				i,
-				expectedTemplate,
-				mockLogger.Message,
+				c.Config.Prefix,
+				record.Prefix,
 			)
 		}
 
-		if !isSameArrays(c.Messages, mockLogger.Args) {
-			t.Errorf("TC %d: The inputs are not the same!",
+		expectedMessage := fmt.Sprintf(c.Template, c.Messages...)
+		if expectedMessage != record.Message {
+			t.Errorf("TC %d: The templated messages are not the same! Expect %s, Actual %s",
 				i,
+				expectedMessage,
+				record.Message,
 			)
 		}
 	}
@@ -420,14 +414,10 @@ func TestLogger_SkipWarn(t *testing.T) {
 
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c)
+		log, mockBackend := newLoggerWithMocks(c)
 		log.Warn("hello", "goodbye")
 
-		if mockLogger.CallType != "" {
-			t.Errorf("TC %d: Expect Warn not to be called.", i)
-		}
-
-		if len(mockLogger.Args) != 0 {
+		if len(mockBackend.Records) != 0 {
 			t.Errorf("TC %d: Expect Warn not to be called.", i)
 		}
 	}
@@ -476,29 +466,27 @@ func TestLogger_Warn(t *testing.T) {
 	}
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c.Config)
+		log, mockBackend := newLoggerWithMocks(c.Config)
 		log.Warn(c.Messages...)
 
-		if mockLogger.CallType != "Warn" {
-			t.Errorf("TC %d: Expect the call type to be \"Warn\", got \"%s\" instead",
+		record := mockBackend.Last()
+		if record.Level != WARN {
+			t.Errorf("TC %d: Expect the record level to be %s, got %s instead",
 				i,
-				mockLogger.CallType,
+				WARN,
+				record.Level,
 			)
 		}
 
-		if actualPrefix, ok := mockLogger.Args[0].(string); !ok {
-			t.Errorf("TC %d: The first argument passed to the method should be string!",
-				i,
-			)
-		} else if actualPrefix != c.Config.Prefix {
+		if record.Prefix != c.Config.Prefix {
 			t.Errorf("TC %d: Expects prefix %s, received %s instead",
 				i,
 				c.Config.Prefix,
-				actualPrefix,
+				record.Prefix,
 			)
 		}
 
-		if !isSameArrays(c.Messages, mockLogger.Args[1:]) {
+		if record.Message != fmt.Sprint(c.Messages...) {
 			t.Errorf("TC %d: The inputs are not the same!",
 				i,
 			)
@@ -520,14 +508,10 @@ func TestLogger_SkipWarnf(t *testing.T) {
 
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c)
+		log, mockBackend := newLoggerWithMocks(c)
 		log.Warnf("%s: %s", "hello", "goodbye")
 
-		if mockLogger.CallType != "" {
-			t.Errorf("TC %d: Expect Warnf not to be called.", i)
-		}
-
-		if len(mockLogger.Args) != 0 {
+		if len(mockBackend.Records) != 0 {
 			t.Errorf("TC %d: Expect Warnf not to be called.", i)
 		}
 	}
@@ -562,28 +546,32 @@ func TestLogger_Warnf(t *testing.T) {
 	}
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c.Config)
+		log, mockBackend := newLoggerWithMocks(c.Config)
 		log.Warnf(c.Template, c.Messages...)
 
-		if mockLogger.CallType != "Warnf" {
-			t.Errorf("Test case %d: Expect the call type to be \"Warnf\", got \"%s\" instead",
+		record := mockBackend.Last()
+		if record.Level != WARN {
+			t.Errorf("Test case %d: Expect the record level to be %s, got %s instead",
 				i,
-				mockLogger.CallType,
+				WARN,
+				record.Level,
 			)
 		}
 
-		expectedTemplate := c.Config.Prefix + c.Template
-		if expectedTemplate != mockLogger.Message {
-			t.Errorf("TC %d: The templating message are not the same! Expect %s, Actual %s",
+		if record.Prefix != c.Config.Prefix {
+			t.Errorf("TC %d: Expects prefix %s, received %s instead",
 				i,
-				expectedTemplate,
-				mockLogger.Message,
+				c.Config.Prefix,
+				record.Prefix,
 			)
 		}
 
-		if !isSameArrays(c.Messages, mockLogger.Args) {
-			t.Errorf("TC %d: The inputs are not the same!",
+		expectedMessage := fmt.Sprintf(c.Template, c.Messages...)
+		if expectedMessage != record.Message {
+			t.Errorf("TC %d: The templated messages are not the same! Expect %s, Actual %s",
 				i,
+				expectedMessage,
+				record.Message,
 			)
 		}
 	}
@@ -609,14 +597,10 @@ func TestLogger_SkipVerbose(t *testing.T) {
 
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c)
+		log, mockBackend := newLoggerWithMocks(c)
 		log.Verbose("hello", "goodbye")
 
-		if mockLogger.CallType != "" {
-			t.Errorf("TC %d: Expect Verbose not to be called.", i)
-		}
-
-		if len(mockLogger.Args) != 0 {
+		if len(mockBackend.Records) != 0 {
 			t.Errorf("TC %d: Expect Verbose not to be called.", i)
 		}
 	}
@@ -665,29 +649,27 @@ func TestLogger_Verbose(t *testing.T) {
 	}
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c.Config)
+		log, mockBackend := newLoggerWithMocks(c.Config)
 		log.Verbose(c.Messages...)
 
-		if mockLogger.CallType != "Info" {
-			t.Errorf("TC %d: Expect the call type to be \"Info\", got \"%s\" instead",
+		record := mockBackend.Last()
+		if record.Level != VERBOSE {
+			t.Errorf("TC %d: Expect the record level to be %s, got %s instead",
 				i,
-				mockLogger.CallType,
+				VERBOSE,
+				record.Level,
 			)
 		}
 
-		if actualPrefix, ok := mockLogger.Args[0].(string); !ok {
-			t.Errorf("TC %d: The first argument passed to the method should be string!",
-				i,
-			)
-		} else if actualPrefix != c.Config.Prefix {
+		if record.Prefix != c.Config.Prefix {
 			t.Errorf("TC %d: Expects prefix %s, received %s instead",
 				i,
 				c.Config.Prefix,
-				actualPrefix,
+				record.Prefix,
 			)
 		}
 
-		if !isSameArrays(c.Messages, mockLogger.Args[1:]) {
+		if record.Message != fmt.Sprint(c.Messages...) {
 			t.Errorf("TC %d: The inputs are not the same!",
 				i,
 			)
@@ -713,14 +695,10 @@ func TestLogger_SkipVerbosef(t *testing.T) {
 
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c)
+		log, mockBackend := newLoggerWithMocks(c)
 		log.Verbosef("%s: %s", "hello", "goodbye")
 
-		if mockLogger.CallType != "" {
-			t.Errorf("TC %d: Expect Verbose not to be called.", i)
-		}
-
-		if len(mockLogger.Args) != 0 {
+		if len(mockBackend.Records) != 0 {
 			t.Errorf("TC %d: Expect Verbose not to be called.", i)
 		}
 	}
@@ -755,28 +733,32 @@ func TestLogger_Verbosef(t *testing.T) {
 	}
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c.Config)
+		log, mockBackend := newLoggerWithMocks(c.Config)
 		log.Verbosef(c.Template, c.Messages...)
 
-		if mockLogger.CallType != "Infof" {
-			t.Errorf("Test case %d: Expect the call type to be \"Infof\", got \"%s\" instead",
+		record := mockBackend.Last()
+		if record.Level != VERBOSE {
+			t.Errorf("Test case %d: Expect the record level to be %s, got %s instead",
 				i,
-				mockLogger.CallType,
+				VERBOSE,
+				record.Level,
 			)
 		}
 
-		expectedTemplate := c.Config.Prefix + c.Template
-		if expectedTemplate != mockLogger.Message {
-			t.Errorf("TC %d: The templating message are not the same! Expect %s, Actual %s",
+		if record.Prefix != c.Config.Prefix {
+			t.Errorf("TC %d: Expects prefix %s, received %s instead",
 				i,
-				expectedTemplate,
-				mockLogger.Message,
+				c.Config.Prefix,
+				record.Prefix,
 			)
 		}
 
-		if !isSameArrays(c.Messages, mockLogger.Args) {
-			t.Errorf("TC %d: The inputs are not the same!",
+		expectedMessage := fmt.Sprintf(c.Template, c.Messages...)
+		if expectedMessage != record.Message {
+			t.Errorf("TC %d: The templated messages are not the same! Expect %s, Actual %s",
 				i,
+				expectedMessage,
+				record.Message,
 			)
 		}
 	}
@@ -802,14 +784,10 @@ func TestLogger_SkipInfo(t *testing.T) {
 
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c)
+		log, mockBackend := newLoggerWithMocks(c)
 		log.Info("hello", "goodbye")
 
-		if mockLogger.CallType != "" {
-			t.Errorf("TC %d: Expect Verbose not to be called.", i)
-		}
-
-		if len(mockLogger.Args) != 0 {
+		if len(mockBackend.Records) != 0 {
 			t.Errorf("TC %d: Expect Verbose not to be called.", i)
 		}
 	}
@@ -858,29 +836,27 @@ func TestLogger_Info(t *testing.T) {
 	}
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c.Config)
+		log, mockBackend := newLoggerWithMocks(c.Config)
 		log.Info(c.Messages...)
 
-		if mockLogger.CallType != "Info" {
-			t.Errorf("TC %d: Expect the call type to be \"Info\", got \"%s\" instead",
+		record := mockBackend.Last()
+		if record.Level != INFO {
+			t.Errorf("TC %d: Expect the record level to be %s, got %s instead",
 				i,
-				mockLogger.CallType,
+				INFO,
+				record.Level,
 			)
 		}
 
-		if actualPrefix, ok := mockLogger.Args[0].(string); !ok {
-			t.Errorf("TC %d: The first argument passed to the method should be string!",
-				i,
-			)
-		} else if actualPrefix != c.Config.Prefix {
+		if record.Prefix != c.Config.Prefix {
 			t.Errorf("TC %d: Expects prefix %s, received %s instead",
 				i,
 				c.Config.Prefix,
-				actualPrefix,
+				record.Prefix,
 			)
 		}
 
-		if !isSameArrays(c.Messages, mockLogger.Args[1:]) {
+		if record.Message != fmt.Sprint(c.Messages...) {
 			t.Errorf("TC %d: The inputs are not the same!",
 				i,
 			)
@@ -906,14 +882,10 @@ func TestLogger_SkipInfof(t *testing.T) {
 
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c)
+		log, mockBackend := newLoggerWithMocks(c)
 		log.Infof("%s: %s", "hello", "goodbye")
 
-		if mockLogger.CallType != "" {
-			t.Errorf("TC %d: Expect Verbose not to be called.", i)
-		}
-
-		if len(mockLogger.Args) != 0 {
+		if len(mockBackend.Records) != 0 {
 			t.Errorf("TC %d: Expect Verbose not to be called.", i)
 		}
 	}
@@ -948,28 +920,32 @@ func TestLogger_Infof(t *testing.T) {
 	}
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c.Config)
+		log, mockBackend := newLoggerWithMocks(c.Config)
 		log.Infof(c.Template, c.Messages...)
 
-		if mockLogger.CallType != "Infof" {
-			t.Errorf("Test case %d: Expect the call type to be \"Infof\", got \"%s\" instead",
+		record := mockBackend.Last()
+		if record.Level != INFO {
+			t.Errorf("Test case %d: Expect the record level to be %s, got %s instead",
 				i,
-				mockLogger.CallType,
+				INFO,
+				record.Level,
 			)
 		}
 
-		expectedTemplate := c.Config.Prefix + c.Template
-		if expectedTemplate != mockLogger.Message {
-			t.Errorf("TC %d: The templating message are not the same! Expect %s, Actual %s",
+		if record.Prefix != c.Config.Prefix {
+			t.Errorf("TC %d: Expects prefix %s, received %s instead",
 				i,
-				expectedTemplate,
-				mockLogger.Message,
+				c.Config.Prefix,
+				record.Prefix,
 			)
 		}
 
-		if !isSameArrays(c.Messages, mockLogger.Args) {
-			t.Errorf("TC %d: The inputs are not the same!",
+		expectedMessage := fmt.Sprintf(c.Template, c.Messages...)
+		if expectedMessage != record.Message {
+			t.Errorf("TC %d: The templated messages are not the same! Expect %s, Actual %s",
 				i,
+				expectedMessage,
+				record.Message,
 			)
 		}
 	}
@@ -995,14 +971,10 @@ func TestLogger_SkipDebug(t *testing.T) {
 
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c)
+		log, mockBackend := newLoggerWithMocks(c)
 		log.Debug("hello", "goodbye")
 
-		if mockLogger.CallType != "" {
-			t.Errorf("TC %d: Expect Verbose not to be called.", i)
-		}
-
-		if len(mockLogger.Args) != 0 {
+		if len(mockBackend.Records) != 0 {
 			t.Errorf("TC %d: Expect Verbose not to be called.", i)
 		}
 	}
@@ -1051,29 +1023,27 @@ func TestLogger_Debug(t *testing.T) {
 	}
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c.Config)
+		log, mockBackend := newLoggerWithMocks(c.Config)
 		log.Debug(c.Messages...)
 
-		if mockLogger.CallType != "Info" {
-			t.Errorf("TC %d: Expect the call type to be \"Info\", got \"%s\" instead",
+		record := mockBackend.Last()
+		if record.Level != DEBUG {
+			t.Errorf("TC %d: Expect the record level to be %s, got %s instead",
 				i,
-				mockLogger.CallType,
+				DEBUG,
+				record.Level,
 			)
 		}
 
-		if actualPrefix, ok := mockLogger.Args[0].(string); !ok {
-			t.Errorf("TC %d: The first argument passed to the method should be string!",
-				i,
-			)
-		} else if actualPrefix != c.Config.Prefix {
+		if record.Prefix != c.Config.Prefix {
 			t.Errorf("TC %d: Expects prefix %s, received %s instead",
 				i,
 				c.Config.Prefix,
-				actualPrefix,
+				record.Prefix,
 			)
 		}
 
-		if !isSameArrays(c.Messages, mockLogger.Args[1:]) {
+		if record.Message != fmt.Sprint(c.Messages...) {
 			t.Errorf("TC %d: The inputs are not the same!",
 				i,
 			)
@@ -1100,14 +1070,10 @@ func TestLogger_SkipDebugf(t *testing.T) {
 
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c)
+		log, mockBackend := newLoggerWithMocks(c)
 		log.Debugf("%s: %s", "hello", "goodbye")
 
-		if mockLogger.CallType != "" {
-			t.Errorf("TC %d: Expect Verbose not to be called.", i)
-		}
-
-		if len(mockLogger.Args) != 0 {
+		if len(mockBackend.Records) != 0 {
 			t.Errorf("TC %d: Expect Verbose not to be called.", i)
 		}
 	}
@@ -1143,28 +1109,32 @@ func TestLogger_Debugf(t *testing.T) {
 	}
 
 	for i, c := range testCases {
-		log, mockLogger := newLoggerWithMocks(c.Config)
+		log, mockBackend := newLoggerWithMocks(c.Config)
 		log.Debugf(c.Template, c.Messages...)
 
-		if mockLogger.CallType != "Infof" {
-			t.Errorf("Test case %d: Expect the call type to be \"Infof\", got \"%s\" instead",
+		record := mockBackend.Last()
+		if record.Level != DEBUG {
+			t.Errorf("Test case %d: Expect the record level to be %s, got %s instead",
 				i,
-				mockLogger.CallType,
+				DEBUG,
+				record.Level,
 			)
 		}
 
-		expectedTemplate := c.Config.Prefix + c.Template
-		if expectedTemplate != mockLogger.Message {
-			t.Errorf("TC %d: The templating message are not the same! Expect %s, Actual %s",
+		if record.Prefix != c.Config.Prefix {
+			t.Errorf("TC %d: Expects prefix %s, received %s instead",
 				i,
-				expectedTemplate,
-				mockLogger.Message,
+				c.Config.Prefix,
+				record.Prefix,
 			)
 		}
 
-		if !isSameArrays(c.Messages, mockLogger.Args) {
-			t.Errorf("TC %d: The inputs are not the same!",
+		expectedMessage := fmt.Sprintf(c.Template, c.Messages...)
+		if expectedMessage != record.Message {
+			t.Errorf("TC %d: The templated messages are not the same! Expect %s, Actual %s",
 				i,
+				expectedMessage,
+				record.Message,
 			)
 		}
 	}
@@ -1172,11 +1142,18 @@ func TestLogger_Debugf(t *testing.T) {
 
 
 func TestNewLogger(t *testing.T) {
-	logger := newLogger(LogConfig {
+	logger := newLogger("hello", LogConfig {
 		Prefix: "[hello]",
 		Level: INFO,
 	})
 
+	if logger.name != "hello" {
+		t.Errorf("Expect name to be %s, got %s instead",
+			"hello",
+			logger.name,
+		)
+	}
+
 	// check the log config
 	if logger.config.Prefix != "[hello]" {
 		t.Errorf("Expect config to be the same! Expect prefix %s, got %s instead",
@@ -1192,60 +1169,311 @@ func TestNewLogger(t *testing.T) {
 		)
 	}
 
-	// check the function pointers
-	loggerFatal := reflect.ValueOf(logger.fatal)
-	glogFatal := reflect.ValueOf(glog.Fatal)
+	// without a backend configured, logs go to glog
+	if logger.getConfig().backend() != defaultBackend() {
+		t.Errorf("Expects the default backend to be used, got %T instead",
+			logger.getConfig().backend(),
+		)
+	}
 
-	if (glogFatal.Pointer() != loggerFatal.Pointer()) {
-		t.Error("Expects the fatal method to be the same as glog.Fatal.")
+	mockBackend := &MockBackend{}
+	logger.setConfig(LogConfig{Backend: mockBackend})
+	if logger.getConfig().backend() != Backend(mockBackend) {
+		t.Error("Expects the configured backend to be used.")
 	}
+}
+
+func TestLogger_Record(t *testing.T) {
+	mockNow(t)
+
+	log, mockBackend := newLoggerWithMocks(LogConfig{
+		Level: INFO,
+		Prefix: "[TestLogger_Record]",
+	})
 
-	loggerFatalf := reflect.ValueOf(logger.fatalf)
-	glogFatalf := reflect.ValueOf(glog.Fatalf)
+	log.Infof("%d%%", 100)
 
-	if (glogFatalf.Pointer() != loggerFatalf.Pointer()) {
-		t.Error("Expects the fatalf method to be the same as glog.Fatalf.")
+	expected := Record{
+		Time: testTime,
+		Level: INFO,
+		Module: "test",
+		Prefix: "[TestLogger_Record]",
+		Message: "100%",
+	}
+
+	if !reflect.DeepEqual(mockBackend.Last(), expected) {
+		t.Errorf("Expect record %+v, got %+v instead",
+			expected,
+			mockBackend.Last(),
+		)
+	}
+}
+
+
+func TestLogger_CallerLocation(t *testing.T) {
+	mockExit(t)
+
+	testCases := []struct {
+		Method string
+		Call func(log *logger)
+	}{
+		{"Fatal", func(log *logger) { log.Fatal("hello") }},
+		{"Fatalf", func(log *logger) { log.Fatalf("%s", "hello") }},
+		{"Error", func(log *logger) { log.Error("hello") }},
+		{"Errorf", func(log *logger) { log.Errorf("%s", "hello") }},
+		{"Warn", func(log *logger) { log.Warn("hello") }},
+		{"Warnf", func(log *logger) { log.Warnf("%s", "hello") }},
+		{"Info", func(log *logger) { log.Info("hello") }},
+		{"Infof", func(log *logger) { log.Infof("%s", "hello") }},
+		{"Debug", func(log *logger) { log.Debug("hello") }},
+		{"Debugf", func(log *logger) { log.Debugf("%s", "hello") }},
+		{"Verbose", func(log *logger) { log.Verbose("hello") }},
+		{"Verbosef", func(log *logger) { log.Verbosef("%s", "hello") }},
+	}
+
+	for i, c := range testCases {
+		log, mockBackend := newLoggerWithMocks(LogConfig{
+			Level: VERBOSE,
+			Prefix: "[TestLogger_CallerLocation]",
+		})
+
+		// the call site is on the same line as the function literal
+		fn := runtime.FuncForPC(reflect.ValueOf(c.Call).Pointer())
+		expectedFile, expectedLine := fn.FileLine(fn.Entry())
+
+		c.Call(log)
+
+		if mockBackend.File != expectedFile || mockBackend.Line != expectedLine {
+			t.Errorf("TC %d: Expect %s to be logged at %s:%d, got %s:%d instead",
+				i,
+				c.Method,
+				expectedFile,
+				expectedLine,
+				mockBackend.File,
+				mockBackend.Line,
+			)
+		}
 	}
+}
 
-	loggerError := reflect.ValueOf(logger.error)
-	glogError := reflect.ValueOf(glog.Error)
+func TestLogger_Structured(t *testing.T) {
+	mockNow(t)
+	exitCode := mockExit(t)
 
-	if (glogError.Pointer() != loggerError.Pointer()) {
-		t.Error("Expects the error method to be the same as glog.Error!")
+	testCases := []struct {
+		Level Level
+		Call func(log Logger)
+	}{
+		{FATAL, func(log Logger) { log.Fatalw("charged card", "request_id", 42) }},
+		{ERROR, func(log Logger) { log.Errorw("charged card", "request_id", 42) }},
+		{WARN, func(log Logger) { log.Warnw("charged card", "request_id", 42) }},
+		{INFO, func(log Logger) { log.Infow("charged card", "request_id", 42) }},
+		{DEBUG, func(log Logger) { log.Debugw("charged card", "request_id", 42) }},
+		{VERBOSE, func(log Logger) { log.Verbosew("charged card", "request_id", 42) }},
 	}
 
-	loggerErrorf := reflect.ValueOf(logger.errorf)
-	glogErrorf := reflect.ValueOf(glog.Errorf)
+	for i, c := range testCases {
+		log, mockBackend := newLoggerWithMocks(LogConfig{
+			Level: VERBOSE,
+			Prefix: "[TestLogger_Structured]",
+		})
+
+		c.Call(log.With("user", "bob"))
+
+		expected := Record{
+			Time: testTime,
+			Level: c.Level,
+			Module: "test",
+			Prefix: "[TestLogger_Structured]",
+			Message: "charged card",
+			Fields: []Field{{"user", "bob"}, {"request_id", 42}},
+			exits: c.Level == FATAL,
+		}
+
+		if !reflect.DeepEqual(mockBackend.Last(), expected) {
+			t.Errorf("TC %d: Expect record %+v, got %+v instead",
+				i,
+				expected,
+				mockBackend.Last(),
+			)
+		}
+	}
 
-	if (glogErrorf.Pointer() != loggerErrorf.Pointer()) {
-		t.Error("Expects the errorf method to be the same as glog.Errorf!")
+	if *exitCode != 255 {
+		t.Errorf("Expect Fatalw to exit with code 255, got %d instead", *exitCode)
 	}
+}
+
+func TestLogger_SkipStructured(t *testing.T) {
+	log, mockBackend := newLoggerWithMocks(LogConfig{
+		Level: WARN,
+		Prefix: "[TestLogger_SkipStructured]",
+	})
 
-	loggerWarn := reflect.ValueOf(logger.warn)
-	glogWarn := reflect.ValueOf(glog.Warning)
+	log.Infow("hello", "a", 1)
+	log.Debugw("hello", "a", 1)
+	log.With("a", 1).Verbosew("hello")
 
-	if (glogWarn.Pointer() != loggerWarn.Pointer()) {
-		t.Error("Expects the warn method to be the same as glog.Warning!")
+	if len(mockBackend.Records) != 0 {
+		t.Errorf("Expect nothing to be logged, got %+v instead", mockBackend.Records)
 	}
+}
+
+func TestLogger_With(t *testing.T) {
+	log, mockBackend := newLoggerWithMocks(LogConfig{
+		Level: INFO,
+		Prefix: "[TestLogger_With]",
+	})
+
+	requestLog := log.With("request_id", 42)
+	userLog := requestLog.With(Field{"user", "bob"})
 
-	loggerWarnf := reflect.ValueOf(logger.warnf)
-	glogWarnf := reflect.ValueOf(glog.Warningf)
+	userLog.Infof("%s", "hello")
+	if !reflect.DeepEqual(mockBackend.Last().Fields, []Field{{"request_id", 42}, {"user", "bob"}}) {
+		t.Errorf("Expect fields of both With calls, got %v instead", mockBackend.Last().Fields)
+	}
 
-	if (glogWarnf.Pointer() != loggerWarnf.Pointer()) {
-		t.Error("Expects the warnf method to be the same as glog.Warningf!")
+	requestLog.Info("hello")
+	if !reflect.DeepEqual(mockBackend.Last().Fields, []Field{{"request_id", 42}}) {
+		t.Errorf("Expect fields of the parent to be unchanged, got %v instead", mockBackend.Last().Fields)
 	}
 
-	loggerInfo := reflect.ValueOf(logger.info)
-	glogInfo := reflect.ValueOf(glog.Info)
+	log.Info("hello")
+	if mockBackend.Last().Fields != nil {
+		t.Errorf("Expect no fields on the module logger, got %v instead", mockBackend.Last().Fields)
+	}
 
-	if (glogInfo.Pointer() != loggerInfo.Pointer()) {
-		t.Error("Expects the info method to be the same as glog.Info!")
+	// derived loggers follow the level of the module
+	log.setLevel(WARN)
+	userLog.Info("hello")
+	if len(mockBackend.Records) != 3 {
+		t.Errorf("Expect derived loggers to follow the module level, got %+v", mockBackend.Records)
 	}
+}
+
+func TestLogger_FatalHandler(t *testing.T) {
+	exitCode := mockExit(t)
+
+	var handled []*FatalError
+	log, mockBackend := newLoggerWithMocks(LogConfig{
+		Level: NOLOG,
+		OnFatal: func(err *FatalError) {
+			handled = append(handled, err)
+		},
+	})
+
+	requestLog := log.With("request_id", 42)
+
+	log.Fatal("lost ", "connection")
+	log.Fatalf("lost %s", "connection")
+	requestLog.Fatalw("lost connection", "attempt", 3)
 
-	loggerInfof := reflect.ValueOf(logger.infof)
-	glogInfof := reflect.ValueOf(glog.Infof)
+	if *exitCode != -1 {
+		t.Errorf("Expect the handler to replace exiting, got exit code %d", *exitCode)
+	}
+
+	expected := []*FatalError{
+		{Module: "test", Message: "lost connection"},
+		{Module: "test", Message: "lost connection"},
+		{Module: "test", Message: "lost connection", Fields: []Field{{"request_id", 42}, {"attempt", 3}}},
+	}
+
+	if !reflect.DeepEqual(handled, expected) {
+		t.Errorf("Expect the handler to be called with %+v, got %+v instead", expected, handled)
+	}
+
+	if len(mockBackend.Records) != 3 || mockBackend.Last().Level != FATAL {
+		t.Errorf("Expect 3 %s records, got %+v instead", FATAL, mockBackend.Records)
+	}
+
+	if handled[2].Error() != "test: lost connection" {
+		t.Errorf("Expect error %q, got %q instead", "test: lost connection", handled[2].Error())
+	}
+}
+
+func TestLogger_PanicOnFatal(t *testing.T) {
+	exitCode := mockExit(t)
+
+	log, _ := newLoggerWithMocks(LogConfig{
+		Level: INFO,
+		OnFatal: PanicOnFatal,
+	})
+
+	defer func() {
+		err, ok := recover().(*FatalError)
+		if !ok || err.Message != "lost connection" {
+			t.Errorf("Expect to panic with the fatal error, got %v", err)
+		}
+
+		if *exitCode != -1 {
+			t.Errorf("Expect not to exit, got exit code %d", *exitCode)
+		}
+	}()
+
+	log.Fatalf("lost %s", "connection")
+	t.Error("Expect Fatalf to panic.")
+}
+
+// Calls fn, returning the value it panicked with.
+func recoverPanic(fn func()) (value interface{}) {
+	defer func() {
+		value = recover()
+	}()
+
+	fn()
+	return nil
+}
+
+func TestLogger_Panic(t *testing.T) {
+	log, mockBackend := newLoggerWithMocks(LogConfig{
+		Level: INFO,
+		Prefix: "[TestLogger_Panic]",
+	})
+
+	requestLog := log.With("request_id", 42)
+
+	testCases := []struct {
+		Panic func()
+		Expected *PanicError
+	}{
+		{
+			Panic: func() { log.Panic("lost ", "connection") },
+			Expected: &PanicError{Module: "test", Message: "lost connection"},
+		},
+		{
+			Panic: func() { requestLog.Panicf("lost %s", "connection") },
+			Expected: &PanicError{Module: "test", Message: "lost connection", Fields: []Field{{"request_id", 42}}},
+		},
+		{
+			Panic: func() { requestLog.Panicw("lost connection", "attempt", 3) },
+			Expected: &PanicError{Module: "test", Message: "lost connection", Fields: []Field{{"request_id", 42}, {"attempt", 3}}},
+		},
+	}
+
+	for i, c := range testCases {
+		value := recoverPanic(c.Panic)
+		if !reflect.DeepEqual(value, c.Expected) {
+			t.Errorf("TC %d: Expect to panic with %+v, got %+v instead", i, c.Expected, value)
+		}
+
+		record := mockBackend.Last()
+		if record.Level != ERROR || record.Prefix != "[TestLogger_Panic]" || record.Message != c.Expected.Message ||
+			!reflect.DeepEqual(record.Fields, c.Expected.Fields) {
+			t.Errorf("TC %d: Expect an %s record of the message, got %+v instead", i, ERROR, record)
+		}
+	}
+
+	// the level silences the log, but not the panic
+	log.setLevel(NOLOG)
+	if _, ok := recoverPanic(func() { log.Panic("silenced") }).(*PanicError); !ok {
+		t.Error("Expect Panic to panic whichever the level.")
+	}
+
+	if len(mockBackend.Records) != len(testCases) {
+		t.Errorf("Expect %d records, got %+v", len(testCases), mockBackend.Records)
+	}
 
-	if (glogInfof.Pointer() != loggerInfof.Pointer()) {
-		t.Error("Expects the infof method to be the same as glog.Infof!")
+	if err := recoverPanic(func() { log.Panic("lost connection") }).(error); err.Error() != "test: lost connection" {
+		t.Errorf("Expect error %q, got %q instead", "test: lost connection", err.Error())
 	}
 }
@@ -0,0 +1,8 @@
+module github.com/evilwire/golog
+
+go 1.21
+
+require (
+	github.com/golang/glog v1.2.5
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,116 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// A Backend writing each record as a single line JSON object, e.g.
+//
+//	{"time":"2017-03-01T10:00:00Z","level":"INFO","module":"billing",
+//	"prefix":"[billing] ","message":"charged card","caller":"charge.go:42",
+//	"fields":{"request_id":42}}
+//
+// (without the line breaks). "fields" is left out when the record has none.
+// Field values are encoded with encoding/json, falling back to their
+// fmt.Sprint representation when they cannot be; errors are written as their
+// message.
+//
+// Unlike glog, the backend does not buffer: each record is written to the
+// writer with a single Write call. Writers that buffer, such as a
+// *bufio.Writer, are flushed by Flush.
+type JSONBackend struct {
+	lock sync.Mutex
+	writer io.Writer
+}
+
+// Returns a backend writing JSON lines to the writer, e.g. os.Stderr.
+func NewJSONBackend(writer io.Writer) *JSONBackend {
+	return &JSONBackend{
+		writer: writer,
+	}
+}
+
+// Flushes the writer if it implements Flusher, e.g. a *bufio.Writer.
+func (backend *JSONBackend) Flush() error {
+	backend.lock.Lock()
+	defer backend.lock.Unlock()
+
+	if flusher, ok := backend.writer.(Flusher); ok {
+		return flusher.Flush()
+	}
+
+	return nil
+}
+
+func (backend *JSONBackend) Log(depth int, record Record) {
+	caller := "???:1"
+	if _, file, line, ok := runtime.Caller(depth + 1); ok {
+		caller = filepath.Base(file) + ":" + strconv.Itoa(line)
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString(`{"time":`)
+	writeJSON(&buffer, record.Time.Format(time.RFC3339Nano))
+	buffer.WriteString(`,"level":`)
+	writeJSON(&buffer, record.Level.String())
+	buffer.WriteString(`,"module":`)
+	writeJSON(&buffer, record.Module)
+	buffer.WriteString(`,"prefix":`)
+	writeJSON(&buffer, record.Prefix)
+	buffer.WriteString(`,"message":`)
+	writeJSON(&buffer, record.Message)
+	buffer.WriteString(`,"caller":`)
+	writeJSON(&buffer, caller)
+
+	if len(record.Fields) > 0 {
+		buffer.WriteString(`,"fields":{`)
+		for i, field := range record.Fields {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+
+			writeJSON(&buffer, field.Key)
+			buffer.WriteByte(':')
+			writeJSON(&buffer, field.Value)
+		}
+		buffer.WriteByte('}')
+	}
+
+	buffer.WriteString("}\n")
+
+	backend.lock.Lock()
+	defer backend.lock.Unlock()
+
+	backend.writer.Write(buffer.Bytes())
+}
+
+// Writes the JSON encoding of the value, without escaping HTML characters.
+// Errors are written as their message, since most have no exported fields,
+// and values that cannot be encoded as their fmt.Sprint string.
+func writeJSON(buffer *bytes.Buffer, value interface{}) {
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(value); err != nil {
+		encoded.Reset()
+		encoder.Encode(fmt.Sprint(value))
+	}
+
+	// Encode terminates every value with a newline
+	buffer.Write(bytes.TrimSuffix(encoded.Bytes(), []byte("\n")))
+}
+
+var _ Backend = (*JSONBackend)(nil)
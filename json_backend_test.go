@@ -0,0 +1,137 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"runtime"
+	"strconv"
+	"path/filepath"
+	"testing"
+)
+
+// Returns the file:line of the function literal, as it would be reported by
+// the JSON backend for a log issued within it.
+func callerOf(fn interface{}) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	file, line := f.FileLine(f.Entry())
+	return filepath.Base(file) + ":" + strconv.Itoa(line)
+}
+
+func TestJSONBackend(t *testing.T) {
+	mockNow(t)
+	mockExit(t)
+
+	testCases := []struct {
+		Level string
+		Call func(log Logger)
+	}{
+		{"FATAL", func(log Logger) { log.Fatal("hello") }},
+		{"FATAL", func(log Logger) { log.Fatalf("%s", "hello") }},
+		{"ERROR", func(log Logger) { log.Error("hello") }},
+		{"ERROR", func(log Logger) { log.Errorf("%s", "hello") }},
+		{"WARN", func(log Logger) { log.Warn("hello") }},
+		{"WARN", func(log Logger) { log.Warnf("%s", "hello") }},
+		{"INFO", func(log Logger) { log.Info("hello") }},
+		{"INFO", func(log Logger) { log.Infof("%s", "hello") }},
+		{"DEBUG", func(log Logger) { log.Debug("hello") }},
+		{"DEBUG", func(log Logger) { log.Debugf("%s", "hello") }},
+		{"VERBOSE", func(log Logger) { log.Verbose("hello") }},
+		{"VERBOSE", func(log Logger) { log.Verbosef("%s", "hello") }},
+	}
+
+	for i, c := range testCases {
+		var output bytes.Buffer
+		log := newLogger("billing", LogConfig{
+			Level: VERBOSE,
+			Prefix: "[billing] ",
+			Backend: NewJSONBackend(&output),
+		})
+
+		c.Call(log)
+
+		expected := `{"time":"2017-03-01T10:00:00Z","level":"` + c.Level +
+			`","module":"billing","prefix":"[billing] ","message":"hello","caller":"` +
+			callerOf(c.Call) + `"}` + "\n"
+
+		if output.String() != expected {
+			t.Errorf("TC %d: Expected output %s, actual output %s",
+				i,
+				expected,
+				output.String(),
+			)
+		}
+	}
+}
+
+func TestJSONBackend_Escaping(t *testing.T) {
+	mockNow(t)
+
+	var output bytes.Buffer
+	log := newLogger("billing", LogConfig{
+		Level: INFO,
+		Prefix: "[\"billing\"]\t",
+		Backend: NewJSONBackend(&output),
+	})
+
+	call := func() { log.Infof("said \"hi\"\nand <left> & %s", "\\o/") }
+	call()
+
+	expected := `{"time":"2017-03-01T10:00:00Z","level":"INFO","module":"billing",` +
+		`"prefix":"[\"billing\"]\t","message":"said \"hi\"\nand <left> & \\o/",` +
+		`"caller":"` + callerOf(call) + `"}` + "\n"
+
+	if output.String() != expected {
+		t.Errorf("Expected output %s, actual output %s",
+			expected,
+			output.String(),
+		)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(output.Bytes(), &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, got error %s", err)
+	}
+
+	if decoded["message"] != "said \"hi\"\nand <left> & \\o/" {
+		t.Errorf("Expected message to round trip, got %q", decoded["message"])
+	}
+}
+
+func TestJSONBackend_Fields(t *testing.T) {
+	mockNow(t)
+
+	var output bytes.Buffer
+	log := newLogger("billing", LogConfig{
+		Level: INFO,
+		Prefix: "[billing] ",
+		Backend: NewJSONBackend(&output),
+	})
+
+	fields := []interface{}{
+		"user", "bob \"the builder\"",
+		"amount", 10.5,
+		"tags", []string{"a", "b"},
+		"ratio", math.Inf(1),
+		"err", errors.New("card declined"),
+	}
+
+	call := func() { log.With("request_id", 42).Infow("charged card", fields...) }
+	call()
+
+	// +Inf cannot be encoded in JSON, and is written as a string instead;
+	// errors are written as their message rather than as an empty object
+	expected := `{"time":"2017-03-01T10:00:00Z","level":"INFO","module":"billing",` +
+		`"prefix":"[billing] ","message":"charged card","caller":"` + callerOf(call) + `",` +
+		`"fields":{"request_id":42,"user":"bob \"the builder\"","amount":10.5,` +
+		`"tags":["a","b"],"ratio":"+Inf","err":"card declined"}}` + "\n"
+
+	if output.String() != expected {
+		t.Errorf("Expected output %s, actual output %s",
+			expected,
+			output.String(),
+		)
+	}
+}
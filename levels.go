@@ -2,42 +2,50 @@
 package golog
 
 import (
-	"github.com/golang/glog"
+	"fmt"
 	"strings"
 )
 
-// An alias for glog.Level, declares the enum type but restricts the user
-// from being able to create instances
-type level glog.Level
+// Declares the enum type, with the same representation as glog.Level. Levels
+// print as their names, e.g. "INFO", and can be used directly in JSON/YAML
+// configuration (encoding.TextMarshaler and encoding.TextUnmarshaler) and as
+// command-line flags (flag.Value).
+type Level int32
 
 // Enum representing log levels
 const (
 
 	// do not log except fatal
-	NOLOG = level(-1)
+	NOLOG = Level(-1)
+
+	// the level of fatal records passed to a Backend; not a level modules
+	// can be configured with by name (see GetLevel), and set in code the same
+	// as NOLOG
+	FATAL = Level(0)
 
 	// logging only errors
-	ERROR = level(1)
+	ERROR = Level(1)
 
 	// logging errors and warning
-	WARN = level(2)
+	WARN = Level(2)
 
 	// second level of text-based logging
-	INFO = level(3)
+	INFO = Level(3)
 
 	// third and last level of text-based logging
-	DEBUG = level(4)
+	DEBUG = Level(4)
 
 	// first level of text-based logging
-	VERBOSE = level(5)
+	VERBOSE = Level(5)
 
 )
 
 // Retrieve a certain level by name, and if the name is not recognised returns NOLOG,
-// false to indicate that the level is not recognized.
+// false to indicate that the level is not recognized. FATAL is not recognised,
+// since fatal logs are always written.
 //
 // levelName (string) - the name of the level
-func GetLevel(levelName string) (level, bool) {
+func GetLevel(levelName string) (Level, bool) {
 	levelName = strings.ToUpper(levelName)
 
 	switch (levelName) {
@@ -61,4 +69,68 @@ func GetLevel(levelName string) (level, bool) {
 	}
 
 	return NOLOG, false
-}
\ No newline at end of file
+}
+
+// Returns the name of the level, e.g. "INFO", or "Level(n)" for values that
+// are not one of the declared levels.
+func (l Level) String() string {
+	switch l {
+	case NOLOG:
+		return "NOLOG"
+
+	case FATAL:
+		return "FATAL"
+
+	case ERROR:
+		return "ERROR"
+
+	case WARN:
+		return "WARN"
+
+	case INFO:
+		return "INFO"
+
+	case DEBUG:
+		return "DEBUG"
+
+	case VERBOSE:
+		return "VERBOSE"
+	}
+
+	return fmt.Sprintf("Level(%d)", int32(l))
+}
+
+// Marshals the level to its name. Fails for values that are not one of the
+// declared levels, so that they do not end up in configuration files. FATAL
+// marshals to its name, e.g. for records, but cannot be unmarshalled.
+func (l Level) MarshalText() ([]byte, error) {
+	if _, ok := GetLevel(l.String()); !ok && l != FATAL {
+		return nil, fmt.Errorf("golog: cannot marshal unknown level %d", int32(l))
+	}
+
+	return []byte(l.String()), nil
+}
+
+// Unmarshals the level from its (case insensitive) name.
+func (l *Level) UnmarshalText(text []byte) error {
+	parsed, ok := GetLevel(string(text))
+	if !ok {
+		return fmt.Errorf("golog: unknown level %q", string(text))
+	}
+
+	*l = parsed
+	return nil
+}
+
+// Sets the level from its name; implements flag.Value, e.g.
+//
+//	level := golog.INFO
+//	flag.Var(&level, "log-level", "the level of the logs")
+func (l *Level) Set(value string) error {
+	return l.UnmarshalText([]byte(value))
+}
+
+// Returns the level; implements flag.Getter.
+func (l *Level) Get() interface{} {
+	return *l
+}
@@ -1,13 +1,18 @@
 package golog
 
-import "testing"
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"testing"
+)
 
 
 func TestGetLevel(t *testing.T) {
 	testCases := []struct {
 		Input string
 		Exists bool
-		Level level
+		Level Level
 	}{
 		{
 			"NOLOG",
@@ -70,3 +75,134 @@ func TestGetLevel(t *testing.T) {
 		}
 	}
 }
+
+var allLevels = []Level{NOLOG, ERROR, WARN, INFO, DEBUG, VERBOSE}
+
+func TestLevel_String(t *testing.T) {
+	testCases := []struct {
+		Level Level
+		Name string
+	}{
+		{NOLOG, "NOLOG"},
+		{FATAL, "FATAL"},
+		{ERROR, "ERROR"},
+		{WARN, "WARN"},
+		{INFO, "INFO"},
+		{DEBUG, "DEBUG"},
+		{VERBOSE, "VERBOSE"},
+		{Level(42), "Level(42)"},
+	}
+
+	for i, c := range testCases {
+		if c.Level.String() != c.Name {
+			t.Errorf("TC %d: Expected name %s, actual name %s",
+				i,
+				c.Name,
+				c.Level.String(),
+			)
+		}
+	}
+}
+
+func TestLevel_TextRoundTrip(t *testing.T) {
+	for i, l := range allLevels {
+		text, err := l.MarshalText()
+		if err != nil {
+			t.Errorf("TC %d: Unexpected error marshalling %s: %s", i, l, err)
+			continue
+		}
+
+		var parsed Level
+		if err := parsed.UnmarshalText(text); err != nil {
+			t.Errorf("TC %d: Unexpected error unmarshalling %s: %s", i, text, err)
+			continue
+		}
+
+		if parsed != l {
+			t.Errorf("TC %d: Expected level %s, actual level %s", i, l, parsed)
+		}
+	}
+}
+
+func TestLevel_JSONRoundTrip(t *testing.T) {
+	type config struct {
+		Level Level `json:"level"`
+	}
+
+	for i, l := range allLevels {
+		data, err := json.Marshal(config{Level: l})
+		if err != nil {
+			t.Errorf("TC %d: Unexpected error marshalling %s: %s", i, l, err)
+			continue
+		}
+
+		expected := `{"level":"` + l.String() + `"}`
+		if string(data) != expected {
+			t.Errorf("TC %d: Expected JSON %s, actual JSON %s", i, expected, data)
+		}
+
+		var parsed config
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			t.Errorf("TC %d: Unexpected error unmarshalling %s: %s", i, data, err)
+			continue
+		}
+
+		if parsed.Level != l {
+			t.Errorf("TC %d: Expected level %s, actual level %s", i, l, parsed.Level)
+		}
+	}
+}
+
+func TestLevel_UnmarshalTextUnknown(t *testing.T) {
+	l := INFO
+	if err := l.UnmarshalText([]byte("LOUD")); err == nil {
+		t.Error("Expect an error when unmarshalling an unknown level.")
+	}
+
+	if l != INFO {
+		t.Errorf("Expect the level to be unchanged, got %s instead", l)
+	}
+
+	if _, err := Level(42).MarshalText(); err == nil {
+		t.Error("Expect an error when marshalling an unknown level.")
+	}
+
+	// FATAL is the level of records, not one that can be configured
+	if err := l.UnmarshalText([]byte("FATAL")); err == nil {
+		t.Errorf("Expect an error when unmarshalling FATAL, got %s", l)
+	}
+
+	if text, err := FATAL.MarshalText(); err != nil || string(text) != "FATAL" {
+		t.Errorf("Expect FATAL to marshal to its name, got %q and %v", text, err)
+	}
+}
+
+func TestLevel_Flag(t *testing.T) {
+	for i, l := range allLevels {
+		level := INFO
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.Var(&level, "level", "the log level")
+
+		if err := flags.Parse([]string{"-level", l.String()}); err != nil {
+			t.Errorf("TC %d: Unexpected error parsing %s: %s", i, l, err)
+			continue
+		}
+
+		if level != l {
+			t.Errorf("TC %d: Expected level %s, actual level %s", i, l, level)
+		}
+
+		if flags.Lookup("level").Value.(flag.Getter).Get() != l {
+			t.Errorf("TC %d: Expected Get to return %s", i, l)
+		}
+	}
+
+	level := INFO
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.Var(&level, "level", "the log level")
+
+	if err := flags.Parse([]string{"-level", "LOUD"}); err == nil {
+		t.Error("Expect an error when parsing an unknown level.")
+	}
+}
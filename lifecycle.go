@@ -0,0 +1,168 @@
+package golog
+
+import (
+	"io"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Implemented by backends that buffer their output, such as GlogBackend, so
+// that Flush can write it out.
+type Flusher interface {
+	Flush() error
+}
+
+// Returns every backend attached to a module logger, each once, including
+// the default ones (glog, and the default output of the configuration file)
+// which are flushed whether or not a module uses them.
+func registryBackends() []Backend {
+	loggersLock.RLock()
+	defer loggersLock.RUnlock()
+
+	candidates := []Backend{defaultBackend(), ruleBackend()}
+	for _, log := range loggers {
+		candidates = append(candidates, log.getConfig().backend())
+	}
+
+	var backends []Backend
+	seen := make(map[Backend]bool)
+
+	for _, backend := range candidates {
+		// backends that cannot be map keys are flushed as many times as
+		// they are attached
+		if reflect.TypeOf(backend).Comparable() {
+			if seen[backend] {
+				continue
+			}
+
+			seen[backend] = true
+		}
+
+		backends = append(backends, backend)
+	}
+
+	return backends
+}
+
+// Flushes every backend attached to a module logger that implements Flusher,
+// glog included. Returns the first error, having tried every backend.
+//
+// Fatal, Fatalf and Fatalw flush before exiting the process; applications
+// should flush before exiting in any other way, e.g. through Close.
+func Flush() error {
+	var first error
+	for _, backend := range registryBackends() {
+		if flusher, ok := backend.(Flusher); ok {
+			if err := flusher.Flush(); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+
+	return first
+}
+
+// Flushes every backend like Flush, then closes the ones that implement
+// io.Closer, e.g. to stop an AutoFlushBackend. Meant to be called once, as
+// the application shuts down; logs issued afterwards may be lost. Returns the
+// first error, having tried every backend.
+func Close() error {
+	first := Flush()
+
+	for _, backend := range registryBackends() {
+		if closer, ok := backend.(io.Closer); ok {
+			if err := closer.Close(); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+
+	return first
+}
+
+// The interval of an AutoFlushBackend created with a non-positive one.
+const defaultFlushInterval = time.Second
+
+// A Backend flushing another one periodically, for backends that buffer
+// their output and should not hold it for long, e.g. a JSONBackend writing to
+// a *bufio.Writer. Created through NewAutoFlushBackend.
+type AutoFlushBackend struct {
+	backend Backend
+	interval time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+	closeOnce sync.Once
+}
+
+// Returns a backend writing to the given one, and flushing it every interval
+// until closed. The backend must implement Flusher. A non-positive interval
+// is replaced by one second.
+func NewAutoFlushBackend(backend Backend, interval time.Duration) *AutoFlushBackend {
+	if interval <= 0 {
+		interval = defaultFlushInterval
+	}
+
+	autoFlush := &AutoFlushBackend{
+		backend: backend,
+		interval: interval,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go autoFlush.run()
+	return autoFlush
+}
+
+func (backend *AutoFlushBackend) run() {
+	defer close(backend.done)
+
+	ticker := time.NewTicker(backend.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-backend.stop:
+			return
+
+		case <-ticker.C:
+			backend.Flush()
+		}
+	}
+}
+
+func (backend *AutoFlushBackend) Log(depth int, record Record) {
+	backend.backend.Log(depth + 1, record)
+}
+
+// Flushes the wrapped backend.
+func (backend *AutoFlushBackend) Flush() error {
+	if flusher, ok := backend.backend.(Flusher); ok {
+		return flusher.Flush()
+	}
+
+	return nil
+}
+
+// Stops flushing periodically, flushes a last time, and closes the wrapped
+// backend if it implements io.Closer.
+func (backend *AutoFlushBackend) Close() error {
+	var err error
+
+	backend.closeOnce.Do(func() {
+		close(backend.stop)
+		<-backend.done
+
+		err = backend.Flush()
+		if closer, ok := backend.backend.(io.Closer); ok {
+			if closeErr := closer.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+	})
+
+	return err
+}
+
+var _ Backend = (*AutoFlushBackend)(nil)
@@ -0,0 +1,175 @@
+package golog
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// A Backend counting how many times it was flushed and closed.
+type flushingBackend struct {
+	MockBackend
+
+	lock sync.Mutex
+	flushes int
+	closes int
+	err error
+}
+
+func (backend *flushingBackend) Flush() error {
+	backend.lock.Lock()
+	defer backend.lock.Unlock()
+
+	backend.flushes++
+	return backend.err
+}
+
+func (backend *flushingBackend) Close() error {
+	backend.lock.Lock()
+	defer backend.lock.Unlock()
+
+	backend.closes++
+	return nil
+}
+
+func (backend *flushingBackend) counts() (int, int) {
+	backend.lock.Lock()
+	defer backend.lock.Unlock()
+
+	return backend.flushes, backend.closes
+}
+
+func TestFlush(t *testing.T) {
+	resetRegistry(t)
+
+	shared := &flushingBackend{}
+	failing := &flushingBackend{err: errors.New("disk full")}
+
+	Setup("db", LogConfig{Backend: shared})
+	Setup("http", LogConfig{Backend: shared})
+	Setup("billing", LogConfig{Backend: failing})
+	Setup("cache", LogConfig{Backend: &MockBackend{}})
+
+	if err := Flush(); err == nil || err.Error() != "disk full" {
+		t.Errorf("Expect the error of the failing backend, got %v", err)
+	}
+
+	if flushes, closes := shared.counts(); flushes != 1 || closes != 0 {
+		t.Errorf("Expect a shared backend to be flushed once, got %d flushes and %d closes", flushes, closes)
+	}
+
+	if flushes, _ := failing.counts(); flushes != 1 {
+		t.Errorf("Expect every backend to be flushed, got %d flushes", flushes)
+	}
+
+	if err := Close(); err == nil {
+		t.Error("Expect Close to report the error of the failing backend.")
+	}
+
+	if flushes, closes := shared.counts(); flushes != 2 || closes != 1 {
+		t.Errorf("Expect Close to flush and close, got %d flushes and %d closes", flushes, closes)
+	}
+}
+
+func TestLogger_FatalFlushes(t *testing.T) {
+	resetRegistry(t)
+	exitCode := mockExit(t)
+
+	other := &flushingBackend{}
+	Setup("db", LogConfig{Backend: other})
+
+	fatal := &flushingBackend{}
+	Setup("main", LogConfig{Backend: fatal})
+
+	GetLogger("main").Fatal("exiting")
+
+	if *exitCode != 255 {
+		t.Errorf("Expect to exit with code 255, got %d instead", *exitCode)
+	}
+
+	// once before the fatal record is written, once before exiting
+	if flushes, _ := other.counts(); flushes != 2 {
+		t.Errorf("Expect the other backends to be flushed before exiting, got %d flushes", flushes)
+	}
+
+	if flushes, _ := fatal.counts(); flushes != 2 || len(fatal.Records) != 1 {
+		t.Errorf("Expect the fatal record to be flushed before exiting, got %d flushes", flushes)
+	}
+}
+
+func TestAutoFlushBackend(t *testing.T) {
+	var output bytes.Buffer
+	var lock sync.Mutex
+
+	buffered := bufio.NewWriter(lockedWriter{&lock, &output})
+	backend := NewAutoFlushBackend(NewJSONBackend(buffered), 10 * time.Millisecond)
+
+	log, _ := newLoggerWithMocks(LogConfig{Level: INFO})
+	log.setBackend(backend)
+	log.Info("buffered")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		lock.Lock()
+		flushed := strings.Contains(output.String(), `"message":"buffered"`)
+		lock.Unlock()
+
+		if flushed {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("Expect the record to be flushed periodically.")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// the last records are flushed on close
+	log.Info("last")
+	if err := backend.Close(); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if !strings.Contains(output.String(), `"message":"last"`) {
+		t.Errorf("Expect the last record to be flushed on close, got %s", output.String())
+	}
+
+	if err := backend.Close(); err != nil {
+		t.Errorf("Expect closing again to do nothing, got %s", err)
+	}
+}
+
+func TestAutoFlushBackend_DefaultInterval(t *testing.T) {
+	for i, interval := range []time.Duration{0, -time.Second} {
+		backend := NewAutoFlushBackend(&flushingBackend{}, interval)
+		if backend.interval != defaultFlushInterval {
+			t.Errorf("TC %d: Expect the interval %s, got %s instead",
+				i,
+				defaultFlushInterval,
+				backend.interval,
+			)
+		}
+
+		if err := backend.Close(); err != nil {
+			t.Errorf("TC %d: Unexpected error: %s", i, err)
+		}
+	}
+}
+
+// Guards the writes to a buffer, which the test reads concurrently.
+type lockedWriter struct {
+	lock *sync.Mutex
+	buffer *bytes.Buffer
+}
+
+func (writer lockedWriter) Write(data []byte) (int, error) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	return writer.buffer.Write(data)
+}
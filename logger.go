@@ -27,7 +27,12 @@
 //
 package golog
 
-import "github.com/golang/glog"
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
 
 
 // Represents the log configuration, and contains a way to configure the
@@ -37,101 +42,312 @@ type LogConfig struct {
 	Prefix string
 
 	// the level of the log
-	Level level
+	Level Level
+
+	// where the logs are written to; if not set, the default output of the
+	// configuration file, or else glog (a TextBackend writing to stderr when
+	// built with the noglog tag)
+	Backend Backend
+
+	// what happens once a fatal message has been logged; the process exits
+	// with code 255 if not set
+	OnFatal FatalHandler
+}
+
+
+// The number of stack frames between logger.output and the application code
+// calling one of the public logger methods.
+const callerDepth = 3
+
+// Exits the process after a fatal log; abstracted out for unit testing.
+var exit = os.Exit
+
+// Returns the time of a log; abstracted out for unit testing.
+var now = time.Now
+
+// Handles a fatal message once it has been logged, in place of exiting the
+// process, e.g. to run shutdown hooks before exiting. If the handler returns,
+// so does the call to Fatal, Fatalf or Fatalw.
+//
+// Since glog exits on fatal logs, fatal messages of a logger with a handler
+// are written to glog with the "error" declaration and a "[FATAL] " tag
+// instead.
+type FatalHandler func(err *FatalError)
+
+// Panics with the fatal error, e.g. so that tests of code calling Fatal can
+// recover from it.
+func PanicOnFatal(err *FatalError) {
+	panic(err)
 }
 
 
-// Abstract out the log and logf functions for unit testing
-// Represents the log function
-type logFun func (args ...interface{})
+// Represents a module logger. GetLogger returns the glog backed
+// implementation; NopLogger and RecordingLogger can be injected in its
+// place, e.g. in unit tests.
+type Logger interface {
+	Fatal(args ...interface{})
+	Fatalf(message string, args ...interface{})
+
+	// Log with the "error" declaration, then panic with a *PanicError
+	Panic(args ...interface{})
+	Panicf(message string, args ...interface{})
+
+	Error(args ...interface{})
+	Errorf(message string, args ...interface{})
+
+	Warn(args ...interface{})
+	Warnf(message string, args ...interface{})
 
-// Represents the log + format function
-type logfFun func (message string, args ...interface{})
+	Info(args ...interface{})
+	Infof(message string, args ...interface{})
+
+	Debug(args ...interface{})
+	Debugf(message string, args ...interface{})
+
+	Verbose(args ...interface{})
+	Verbosef(message string, args ...interface{})
+
+	// Structured variants, taking alternating keys and values (or Fields)
+	// that are passed on to the backend, e.g.
+	//
+	//	logger.Infow("charged card", "request_id", id, "amount", amount)
+	Fatalw(message string, keysAndValues ...interface{})
+	Panicw(message string, keysAndValues ...interface{})
+	Errorw(message string, keysAndValues ...interface{})
+	Warnw(message string, keysAndValues ...interface{})
+	Infow(message string, keysAndValues ...interface{})
+	Debugw(message string, keysAndValues ...interface{})
+	Verbosew(message string, keysAndValues ...interface{})
+
+	// Returns a logger that attaches the given keys and values (or Fields)
+	// to every log, on top of the ones of this logger.
+	With(keysAndValues ...interface{}) Logger
+}
 
 
 // The base logger class; cannot be instantiated except through
 // GetLogger.
 type logger struct {
+	// whether the logger was configured through Setup or SetLevel, as opposed
+	// to inheriting its level from an ancestor; guarded by loggersLock
+	explicit bool
+
+	// guards config, which may be changed at runtime through Setup and
+	// SetLevel while other goroutines are logging
+	lock sync.RWMutex
 	config LogConfig
 
-	fatal logFun
-	fatalf logfFun
+	// the name of the module
+	name string
+
+	// the logger returned by GetLogger that this logger was derived from
+	// through With, and which holds the configuration; nil for the module
+	// logger itself
+	root *logger
+
+	// fields attached to every log through With
+	fields []Field
+
+	// the number of logs emitted and suppressed per level; kept by the module
+	// logger for the loggers derived from it
+	counts levelCounts
+}
+
+
+// Returns a snapshot of the logger's current configuration.
+func (log *logger) getConfig() LogConfig {
+	if log.root != nil {
+		return log.root.getConfig()
+	}
+
+	log.lock.RLock()
+	defer log.lock.RUnlock()
+
+	return log.config
+}
+
+func (log *logger) setConfig(config LogConfig) {
+	log.lock.Lock()
+	log.config = config
+	log.lock.Unlock()
+}
+
+func (log *logger) setLevel(l Level) {
+	log.lock.Lock()
+	log.config.Level = l
+	log.lock.Unlock()
+}
+
+func (log *logger) setPrefix(prefix string) {
+	log.lock.Lock()
+	log.config.Prefix = prefix
+	log.lock.Unlock()
+}
+
+func (log *logger) setBackend(backend Backend) {
+	log.lock.Lock()
+	log.config.Backend = backend
+	log.lock.Unlock()
+}
+
+// Returns the configured backend, defaulting to the one of the configuration
+// file (see LoadConfig), or else glog.
+func (config LogConfig) backend() Backend {
+	if config.Backend != nil {
+		return config.Backend
+	}
+
+	return ruleBackend()
+}
+
+// Whether a message of level l passes the configured level. Fatal messages
+// are always logged.
+func (config LogConfig) enabled(l Level) bool {
+	return l == FATAL || config.Level >= l
+}
+
+// Whether a message of level l passes the configured level, or glog's
+// verbosity (see HonorGlogVerbosity). depth is the number of stack frames
+// between the caller and the application code issuing the log, as for
+// Backend.Log, so that -vmodule matches the file of the application code.
+func (config LogConfig) allows(depth int, l Level) bool {
+	if config.enabled(l) {
+		return true
+	}
+
+	return l > INFO && glogVerbose(depth + 1, l)
+}
+
+// Whether a message of level l passes the configured level, or glog's
+// verbosity (see HonorGlogVerbosity), counting it as emitted or suppressed.
+// Must be called from log, logf or logw so that the caller depth is right.
+func (log *logger) enabled(config LogConfig, l Level) bool {
+	counts := &log.counts
+	if log.root != nil {
+		counts = &log.root.counts
+	}
+
+	enabled := config.allows(callerDepth, l)
+	counts.add(l, enabled)
 
-	error logFun
-	errorf logfFun
+	return enabled
+}
 
-	warn logFun
-	warnf logfFun
+// Hands the record over to the backend; must be called from log, logf or
+// logw so that the caller depth is right.
+func (log *logger) output(config LogConfig, l Level, message string, fields []Field) {
+	exits := l == FATAL && config.OnFatal == nil
+	if exits {
+		// glog exits as soon as it has written a fatal log, so the other
+		// backends are flushed first
+		Flush()
+	}
 
-	info logFun
-	infof logfFun
+	config.backend().Log(callerDepth, Record{
+		Time: now(),
+		Level: l,
+		Module: log.name,
+		Prefix: config.Prefix,
+		Message: message,
+		Fields: fields,
+		exits: exits,
+	})
 }
 
+func (log *logger) log(l Level, args ...interface{}) {
+	config := log.getConfig()
+	if log.enabled(config, l) {
+		log.output(config, l, fmt.Sprint(args...), log.fields)
+	}
+}
 
-func (log *logger) log(l level, args ...interface{}) {
-	if log.config.Level >= l {
-		args = append([]interface{}{log.config.Prefix}, args...)
-		log.info(args...)
+func (log *logger) logf(l Level, message string, args ...interface{}) {
+	config := log.getConfig()
+	if log.enabled(config, l) {
+		log.output(config, l, fmt.Sprintf(message, args...), log.fields)
 	}
 }
 
-func (log *logger) logf(l level, message string, args ...interface{}) {
-	if log.config.Level >= l {
-		message = log.config.Prefix + message
-		log.infof(message, args...)
+func (log *logger) logw(l Level, message string, keysAndValues []interface{}) {
+	config := log.getConfig()
+	if log.enabled(config, l) {
+		fields := appendFields(log.fields, keysAndValues)
+		log.output(config, l, message, fields)
 	}
 }
 
-// Logs arguments with the "fatal" declaration and exists with code 255.
-// Logs will have an "F" at the beginning, and include the line no. where
-// the log is issued.
+// Flushes every backend (see Flush), then exits with code 255 after a fatal
+// log, or hands the message over to the configured FatalHandler.
+func (log *logger) fatal(message string, fields []Field) {
+	Flush()
+
+	handler := log.getConfig().OnFatal
+	if handler == nil {
+		exit(255)
+		return
+	}
+
+	handler(&FatalError{
+		Module: log.name,
+		Message: message,
+		Fields: fields,
+	})
+}
+
+// Logs arguments with the "fatal" declaration and exits, unless the logger
+// has a FatalHandler: glog exits once it has written the log along with the
+// stacks of every goroutine, other backends with code 255. Logs will have an
+// "F" at the beginning, and include the line no. where the log is issued.
 func (log *logger) Fatal(args ...interface{}) {
-	args = append([]interface{}{log.config.Prefix}, args...)
-	log.fatal(args...)
+	log.log(FATAL, args...)
+	log.fatal(fmt.Sprint(args...), log.fields)
 }
 
 // Logs a templated message with the "fatal" declaration and exists with
 // code 255. Same as Fatal, except the arguments will be used to instantiate
 // a templating string.
 func (log *logger) Fatalf(message string, args ...interface{}) {
-	message = log.config.Prefix + message
-	log.fatalf(message, args...)
+	log.logf(FATAL, message, args...)
+	log.fatal(fmt.Sprintf(message, args...), log.fields)
+}
+
+// Logs arguments with the "error" declaration, then panics with a *PanicError
+// carrying the message, e.g. for a recover() in an HTTP middleware to turn
+// into a 500. The log is subject to the level of the logger; the panic is not.
+func (log *logger) Panic(args ...interface{}) {
+	log.log(ERROR, args...)
+	panicWith(log.name, fmt.Sprint(args...), log.fields)
+}
+
+// Logs a templated message with the "error" declaration, then panics like
+// Panic.
+func (log *logger) Panicf(message string, args ...interface{}) {
+	log.logf(ERROR, message, args...)
+	panicWith(log.name, fmt.Sprintf(message, args...), log.fields)
 }
 
 // Logs arguments with the "error" declaration. Logs will have an "E" at the
 // beginning, and include the line no.
 func (log *logger) Error(args ...interface{}) {
-	if log.config.Level >= ERROR {
-		args = append([]interface{}{log.config.Prefix}, args...)
-		log.error(args...)
-	}
+	log.log(ERROR, args...)
 }
 
 // Logs a templated message with the "error" declaration, same as Error,
 // except formats the argument according to the message template.
 func (log *logger) Errorf(message string, args ...interface{}) {
-	if log.config.Level >= ERROR {
-		message = log.config.Prefix + message
-		log.errorf(message, args...)
-	}
+	log.logf(ERROR, message, args...)
 }
 
 // Logs arguments with the "warning" declaration. Begins with a "W" and
 // includes the line no.
 func (log *logger) Warn(args ...interface{}) {
-	if log.config.Level >= WARN {
-		args = append([]interface{}{log.config.Prefix}, args...)
-		log.warn(args...)
-	}
+	log.log(WARN, args...)
 }
 
 // Logs a templated message with the "warning" declaration. Like Warn,
 // except formats the args according to templates.
 func (log *logger) Warnf(message string, args ...interface{}) {
-	if log.config.Level >= WARN {
-		message = log.config.Prefix + message
-		log.warnf(message, args...)
-	}
+	log.logf(WARN, message, args...)
 }
 
 // Logs arguments with the "info" designation. Starts with an "I". This should
@@ -147,9 +363,9 @@ func (log *logger) Infof(message string, args ...interface{}) {
 	log.logf(INFO, message, args...)
 }
 
-// Logs arguments with the "debug" designation. Log messages start with an "I".
-// This should be used for detailed information that can be used for debugging
-// somewhat expensive code paths.
+// Logs arguments with the "debug" designation. Log messages start with an "I",
+// and are tagged "[DEBUG]" after the prefix. This should be used for detailed
+// information that can be used for debugging somewhat expensive code paths.
 func (log *logger) Debug(args ...interface{}) {
 	log.log(DEBUG, args...)
 }
@@ -170,43 +386,67 @@ func (log *logger) Verbosef(message string, args ...interface{}) {
 	log.logf(VERBOSE, message, args...)
 }
 
-//
-func newLogger(config LogConfig) *logger {
-	return &logger{
-		config: config,
-		fatal:  glog.Fatal,
-		fatalf: glog.Fatalf,
-		error:  glog.Error,
-		errorf: glog.Errorf,
-		warn:   glog.Warning,
-		warnf:  glog.Warningf,
-		info:    glog.Info,
-		infof:   glog.Infof,
-	}
+// Logs a message with key/value pairs with the "fatal" declaration, and
+// exits with code 255 like Fatal.
+func (log *logger) Fatalw(message string, keysAndValues ...interface{}) {
+	log.logw(FATAL, message, keysAndValues)
+	log.fatal(message, appendFields(log.fields, keysAndValues))
 }
 
-var loggers map[string]*logger = make(map[string]*logger)
+// Logs a message with key/value pairs with the "error" declaration, then
+// panics like Panic; the fields are carried by the *PanicError.
+func (log *logger) Panicw(message string, keysAndValues ...interface{}) {
+	log.logw(ERROR, message, keysAndValues)
+	panicWith(log.name, message, appendFields(log.fields, keysAndValues))
+}
 
-// Get a logger by name. If the logger has not been previously setup
-// the logger will be configured (and setup) with default level of "DEBUG"
-// and the default prefix of "[$name] "
-func GetLogger(name string) *logger {
-	if logger, ok := loggers[name]; ok {
-		return logger
-	}
+// Logs a message with key/value pairs with the "error" declaration.
+func (log *logger) Errorw(message string, keysAndValues ...interface{}) {
+	log.logw(ERROR, message, keysAndValues)
+}
 
-	l := newLogger(LogConfig{
-		Level: DEBUG,
-		Prefix: "[" + name + "] ",
-	})
+// Logs a message with key/value pairs with the "warning" declaration.
+func (log *logger) Warnw(message string, keysAndValues ...interface{}) {
+	log.logw(WARN, message, keysAndValues)
+}
+
+// Logs a message with key/value pairs with the "info" designation.
+func (log *logger) Infow(message string, keysAndValues ...interface{}) {
+	log.logw(INFO, message, keysAndValues)
+}
+
+// Logs a message with key/value pairs with the "debug" designation.
+func (log *logger) Debugw(message string, keysAndValues ...interface{}) {
+	log.logw(DEBUG, message, keysAndValues)
+}
 
-	loggers[name] = l
-	return l
+// Logs a message with key/value pairs with the "verbose" designation.
+func (log *logger) Verbosew(message string, keysAndValues ...interface{}) {
+	log.logw(VERBOSE, message, keysAndValues)
 }
 
-// Sets up a logger by name, and with a set of log configurations. This
-// should be called at the start of the application.
-func Setup(name string, logConfig LogConfig) {
-	l := newLogger(logConfig)
-	loggers[name] = l
+// Returns a logger for the same module that attaches the key/value pairs
+// to every log. The returned logger follows the configuration of the module,
+// including changes made afterwards through Setup and SetLevel.
+func (log *logger) With(keysAndValues ...interface{}) Logger {
+	root := log
+	if log.root != nil {
+		root = log.root
+	}
+
+	return &logger{
+		name: log.name,
+		root: root,
+		fields: appendFields(log.fields, keysAndValues),
+	}
+}
+
+var _ Logger = (*logger)(nil)
+
+//
+func newLogger(name string, config LogConfig) *logger {
+	return &logger{
+		name: name,
+		config: config,
+	}
 }
@@ -0,0 +1,91 @@
+package golog
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// The content type of the Prometheus text exposition format.
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// An http.Handler exposing the counts of the module loggers in the Prometheus
+// text exposition format, e.g.
+//
+//	http.Handle("/metrics/golog", golog.MetricsHandler{})
+//
+// The metrics are
+//
+//	golog_logs_emitted_total{module="db",level="ERROR"}  logs written
+//	golog_logs_dropped_total{module="db",level="DEBUG"}  logs suppressed by the level check
+//	golog_module_level{module="db"}                      the level of the module, e.g. 3 for INFO
+//
+// Every module and level is exposed, including the counters that are still
+// zero, so that rates can be computed from the first scrape.
+type MetricsHandler struct{}
+
+func (MetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buffer bytes.Buffer
+	writeMetrics(&buffer, registryStats())
+
+	w.Header().Set("Content-Type", metricsContentType)
+	w.Write(buffer.Bytes())
+}
+
+func writeMetrics(buffer *bytes.Buffer, stats []moduleStats) {
+	counters := []struct {
+		name string
+		help string
+		counts func(moduleStats) map[Level]int64
+	}{
+		{
+			name: "golog_logs_emitted_total",
+			help: "Logs written, by module and level.",
+			counts: func(stats moduleStats) map[Level]int64 {
+				return stats.emitted
+			},
+		},
+		{
+			name: "golog_logs_dropped_total",
+			help: "Logs suppressed by the level of the module, by module and level.",
+			counts: func(stats moduleStats) map[Level]int64 {
+				return stats.suppressed
+			},
+		},
+	}
+
+	for _, counter := range counters {
+		fmt.Fprintf(buffer, "# HELP %s %s\n", counter.name, counter.help)
+		fmt.Fprintf(buffer, "# TYPE %s counter\n", counter.name)
+
+		for _, module := range stats {
+			counts := counter.counts(module)
+			for _, l := range logLevels {
+				fmt.Fprintf(buffer, "%s{module=\"%s\",level=\"%s\"} %d\n",
+					counter.name,
+					escapeLabel(module.module),
+					l,
+					counts[l],
+				)
+			}
+		}
+	}
+
+	buffer.WriteString("# HELP golog_module_level The level of the module, from -1 (NOLOG) to 5 (VERBOSE).\n")
+	buffer.WriteString("# TYPE golog_module_level gauge\n")
+
+	for _, module := range stats {
+		fmt.Fprintf(buffer, "golog_module_level{module=\"%s\"} %d\n",
+			escapeLabel(module.module),
+			module.level,
+		)
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Escapes a label value as required by the exposition format.
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
@@ -0,0 +1,54 @@
+package golog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	resetRegistry(t)
+
+	Setup("db", LogConfig{Level: NOLOG, Backend: &MockBackend{}})
+	Setup(`odd"module`, LogConfig{Level: ERROR, Backend: &MockBackend{}})
+
+	GetLogger("db").Debug("dropped")
+	GetLogger("db").With("attempt", 1).Debugw("dropped")
+	GetLogger(`odd"module`).Errorf("written")
+
+	response := httptest.NewRecorder()
+	MetricsHandler{}.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if response.Header().Get("Content-Type") != metricsContentType {
+		t.Errorf("Expect content type %q, got %q instead",
+			metricsContentType,
+			response.Header().Get("Content-Type"),
+		)
+	}
+
+	expected := []string{
+		"# TYPE golog_logs_emitted_total counter\n",
+		"# TYPE golog_logs_dropped_total counter\n",
+		"# TYPE golog_module_level gauge\n",
+		`golog_logs_emitted_total{module="db",level="DEBUG"} 0` + "\n",
+		`golog_logs_dropped_total{module="db",level="DEBUG"} 2` + "\n",
+		`golog_logs_dropped_total{module="db",level="FATAL"} 0` + "\n",
+		`golog_logs_emitted_total{module="odd\"module",level="ERROR"} 1` + "\n",
+		`golog_module_level{module="db"} -1` + "\n",
+		`golog_module_level{module="odd\"module"} 1` + "\n",
+	}
+
+	body := response.Body.String()
+	for i, line := range expected {
+		if !strings.Contains(body, line) {
+			t.Errorf("TC %d: Expect %q in the metrics, got:\n%s", i, line, body)
+		}
+	}
+
+	// two modules, six levels, two counters, plus a level per module and
+	// the HELP and TYPE lines of three metrics
+	if lines := strings.Count(body, "\n"); lines != 2 * 6 * 2 + 2 + 3 * 2 {
+		t.Errorf("Expect %d lines, got %d:\n%s", 2 * 6 * 2 + 2 + 3 * 2, lines, body)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build noglog
+
+package golog
+
+import "os"
+
+// The backend of the modules that have none configured, in place of glog.
+var stderrBackend = NewTextBackend(os.Stderr, TextFormat{})
+
+// Returns the backend of the modules that have none configured: text written
+// to stderr, since glog is not compiled in.
+func defaultBackend() Backend {
+	return stderrBackend
+}
+
+// Does nothing, since glog's -v and -vmodule flags are not registered without
+// glog; kept so that programs calling it also build with the noglog tag.
+func HonorGlogVerbosity(enabled bool) {
+}
+
+// glog's verbosity does not apply without glog.
+func glogVerbose(depth int, l Level) bool {
+	return false
+}
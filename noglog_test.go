@@ -0,0 +1,24 @@
+//go:build noglog
+
+package golog
+
+import "testing"
+
+func TestDefaultBackend(t *testing.T) {
+	if defaultBackend() != stderrBackend {
+		t.Errorf("Expect the default backend to write text to stderr, got %T instead", defaultBackend())
+	}
+}
+
+func TestHonorGlogVerbosity(t *testing.T) {
+	HonorGlogVerbosity(true)
+	defer HonorGlogVerbosity(false)
+
+	log, mockBackend := newLoggerWithMocks(LogConfig{Level: INFO})
+	log.Debug("debug")
+	log.Verbose("verbose")
+
+	if len(mockBackend.Records) != 0 {
+		t.Errorf("Expect the level alone to apply without glog, got %+v", mockBackend.Records)
+	}
+}
@@ -0,0 +1,63 @@
+package golog
+
+import "fmt"
+
+// A Logger that discards everything, including Fatal and Fatalf, which do
+// not exit the process. Panic, Panicf and Panicw still panic, since the code
+// calling them does not expect to carry on. Useful as a default for optional
+// logger fields.
+type NopLogger struct{}
+
+func (NopLogger) Fatal(args ...interface{}) {}
+
+func (NopLogger) Fatalf(message string, args ...interface{}) {}
+
+func (NopLogger) Panic(args ...interface{}) {
+	panicWith("", fmt.Sprint(args...), nil)
+}
+
+func (NopLogger) Panicf(message string, args ...interface{}) {
+	panicWith("", fmt.Sprintf(message, args...), nil)
+}
+
+func (NopLogger) Error(args ...interface{}) {}
+
+func (NopLogger) Errorf(message string, args ...interface{}) {}
+
+func (NopLogger) Warn(args ...interface{}) {}
+
+func (NopLogger) Warnf(message string, args ...interface{}) {}
+
+func (NopLogger) Info(args ...interface{}) {}
+
+func (NopLogger) Infof(message string, args ...interface{}) {}
+
+func (NopLogger) Debug(args ...interface{}) {}
+
+func (NopLogger) Debugf(message string, args ...interface{}) {}
+
+func (NopLogger) Verbose(args ...interface{}) {}
+
+func (NopLogger) Verbosef(message string, args ...interface{}) {}
+
+func (NopLogger) Fatalw(message string, keysAndValues ...interface{}) {}
+
+func (NopLogger) Panicw(message string, keysAndValues ...interface{}) {
+	panicWith("", message, appendFields(nil, keysAndValues))
+}
+
+func (NopLogger) Errorw(message string, keysAndValues ...interface{}) {}
+
+func (NopLogger) Warnw(message string, keysAndValues ...interface{}) {}
+
+func (NopLogger) Infow(message string, keysAndValues ...interface{}) {}
+
+func (NopLogger) Debugw(message string, keysAndValues ...interface{}) {}
+
+func (NopLogger) Verbosew(message string, keysAndValues ...interface{}) {}
+
+func (log NopLogger) With(keysAndValues ...interface{}) Logger {
+	return log
+}
+
+var _ Logger = NopLogger{}
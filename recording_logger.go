@@ -0,0 +1,210 @@
+package golog
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Represents a single call made to a RecordingLogger.
+type RecordedCall struct {
+	// the name of the method called, e.g. "Info" or "Infof"
+	Method string
+
+	// the template passed to the formatting variants, or the message passed
+	// to the structured variants; empty otherwise
+	Message string
+
+	// the arguments passed to the method
+	Args []interface{}
+
+	// the fields attached through With and the structured variants
+	Fields []Field
+}
+
+// Returns the message as it would have been logged, i.e. the template
+// instantiated with the arguments, or the arguments printed one after
+// another.
+func (call RecordedCall) Text() string {
+	if strings.HasSuffix(call.Method, "w") {
+		return call.Message
+	}
+
+	if call.Message != "" {
+		return fmt.Sprintf(call.Message, call.Args...)
+	}
+
+	return fmt.Sprint(call.Args...)
+}
+
+// A Logger that keeps every call in memory, to be inspected by unit tests.
+// Calls are recorded regardless of level, and Fatal and Fatalf do not exit
+// the process; Panic, Panicf and Panicw are recorded, then panic. The zero
+// value is ready to use, and is safe for concurrent use. Loggers returned by
+// With record into the same list of calls.
+type RecordingLogger struct {
+	lock  sync.Mutex
+	calls []RecordedCall
+
+	// the logger the calls are recorded into, for loggers returned by With
+	root *RecordingLogger
+
+	// fields attached through With
+	fields []Field
+}
+
+func (log *RecordingLogger) record(method string, message string, args []interface{}) {
+	log.recordFields(method, message, args, log.fields)
+}
+
+func (log *RecordingLogger) recordFields(method string, message string, args []interface{}, fields []Field) {
+	if log.root != nil {
+		log.root.recordFields(method, message, args, fields)
+		return
+	}
+
+	log.lock.Lock()
+	defer log.lock.Unlock()
+
+	log.calls = append(log.calls, RecordedCall{
+		Method:  method,
+		Message: message,
+		Args:    args,
+		Fields:  fields,
+	})
+}
+
+func (log *RecordingLogger) recordw(method string, message string, keysAndValues []interface{}) {
+	log.recordFields(method, message, nil, appendFields(log.fields, keysAndValues))
+}
+
+// Returns a copy of the calls recorded so far, in the order they were made.
+func (log *RecordingLogger) Calls() []RecordedCall {
+	if log.root != nil {
+		return log.root.Calls()
+	}
+
+	log.lock.Lock()
+	defer log.lock.Unlock()
+
+	calls := make([]RecordedCall, len(log.calls))
+	copy(calls, log.calls)
+	return calls
+}
+
+// Forgets all of the calls recorded so far.
+func (log *RecordingLogger) Reset() {
+	if log.root != nil {
+		log.root.Reset()
+		return
+	}
+
+	log.lock.Lock()
+	defer log.lock.Unlock()
+
+	log.calls = nil
+}
+
+func (log *RecordingLogger) Fatal(args ...interface{}) {
+	log.record("Fatal", "", args)
+}
+
+func (log *RecordingLogger) Fatalf(message string, args ...interface{}) {
+	log.record("Fatalf", message, args)
+}
+
+func (log *RecordingLogger) Panic(args ...interface{}) {
+	log.record("Panic", "", args)
+	panicWith("", fmt.Sprint(args...), log.fields)
+}
+
+func (log *RecordingLogger) Panicf(message string, args ...interface{}) {
+	log.record("Panicf", message, args)
+	panicWith("", fmt.Sprintf(message, args...), log.fields)
+}
+
+func (log *RecordingLogger) Error(args ...interface{}) {
+	log.record("Error", "", args)
+}
+
+func (log *RecordingLogger) Errorf(message string, args ...interface{}) {
+	log.record("Errorf", message, args)
+}
+
+func (log *RecordingLogger) Warn(args ...interface{}) {
+	log.record("Warn", "", args)
+}
+
+func (log *RecordingLogger) Warnf(message string, args ...interface{}) {
+	log.record("Warnf", message, args)
+}
+
+func (log *RecordingLogger) Info(args ...interface{}) {
+	log.record("Info", "", args)
+}
+
+func (log *RecordingLogger) Infof(message string, args ...interface{}) {
+	log.record("Infof", message, args)
+}
+
+func (log *RecordingLogger) Debug(args ...interface{}) {
+	log.record("Debug", "", args)
+}
+
+func (log *RecordingLogger) Debugf(message string, args ...interface{}) {
+	log.record("Debugf", message, args)
+}
+
+func (log *RecordingLogger) Verbose(args ...interface{}) {
+	log.record("Verbose", "", args)
+}
+
+func (log *RecordingLogger) Verbosef(message string, args ...interface{}) {
+	log.record("Verbosef", message, args)
+}
+
+func (log *RecordingLogger) Fatalw(message string, keysAndValues ...interface{}) {
+	log.recordw("Fatalw", message, keysAndValues)
+}
+
+func (log *RecordingLogger) Panicw(message string, keysAndValues ...interface{}) {
+	fields := appendFields(log.fields, keysAndValues)
+	log.recordFields("Panicw", message, nil, fields)
+	panicWith("", message, fields)
+}
+
+func (log *RecordingLogger) Errorw(message string, keysAndValues ...interface{}) {
+	log.recordw("Errorw", message, keysAndValues)
+}
+
+func (log *RecordingLogger) Warnw(message string, keysAndValues ...interface{}) {
+	log.recordw("Warnw", message, keysAndValues)
+}
+
+func (log *RecordingLogger) Infow(message string, keysAndValues ...interface{}) {
+	log.recordw("Infow", message, keysAndValues)
+}
+
+func (log *RecordingLogger) Debugw(message string, keysAndValues ...interface{}) {
+	log.recordw("Debugw", message, keysAndValues)
+}
+
+func (log *RecordingLogger) Verbosew(message string, keysAndValues ...interface{}) {
+	log.recordw("Verbosew", message, keysAndValues)
+}
+
+// Returns a logger recording into the same list of calls, with the key/value
+// pairs attached to every call.
+func (log *RecordingLogger) With(keysAndValues ...interface{}) Logger {
+	root := log
+	if log.root != nil {
+		root = log.root
+	}
+
+	return &RecordingLogger{
+		root:   root,
+		fields: appendFields(log.fields, keysAndValues),
+	}
+}
+
+var _ Logger = (*RecordingLogger)(nil)
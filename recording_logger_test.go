@@ -0,0 +1,149 @@
+package golog
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestRecordingLogger(t *testing.T) {
+	log := &RecordingLogger{}
+
+	log.Fatal("fatal", 1)
+	log.Fatalf("%s %d", "fatalf", 2)
+	log.Error("error")
+	log.Errorf("%s", "errorf")
+	log.Warn("warn")
+	log.Warnf("%s", "warnf")
+	log.Info("info")
+	log.Infof("%s", "infof")
+	log.Debug("debug")
+	log.Debugf("%s", "debugf")
+	log.Verbose("verbose")
+	log.Verbosef("%s", "verbosef")
+
+	expected := []struct {
+		Method string
+		Text string
+	}{
+		{"Fatal", "fatal1"},
+		{"Fatalf", "fatalf 2"},
+		{"Error", "error"},
+		{"Errorf", "errorf"},
+		{"Warn", "warn"},
+		{"Warnf", "warnf"},
+		{"Info", "info"},
+		{"Infof", "infof"},
+		{"Debug", "debug"},
+		{"Debugf", "debugf"},
+		{"Verbose", "verbose"},
+		{"Verbosef", "verbosef"},
+	}
+
+	calls := log.Calls()
+	if len(calls) != len(expected) {
+		t.Fatalf("Expect %d calls to be recorded, got %d instead",
+			len(expected),
+			len(calls),
+		)
+	}
+
+	for i, c := range expected {
+		if calls[i].Method != c.Method {
+			t.Errorf("TC %d: Expect method %s, got %s instead",
+				i,
+				c.Method,
+				calls[i].Method,
+			)
+		}
+
+		if calls[i].Text() != c.Text {
+			t.Errorf("TC %d: Expect text %q, got %q instead",
+				i,
+				c.Text,
+				calls[i].Text(),
+			)
+		}
+	}
+
+	log.Reset()
+	if len(log.Calls()) != 0 {
+		t.Error("Expect no calls to be recorded after Reset.")
+	}
+}
+
+func TestRecordingLogger_Concurrent(t *testing.T) {
+	log := &RecordingLogger{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			log.Infof("message %d", i)
+		}(i)
+	}
+
+	wg.Wait()
+
+	if len(log.Calls()) != 100 {
+		t.Errorf("Expect %d calls to be recorded, got %d instead",
+			100,
+			len(log.Calls()),
+		)
+	}
+}
+
+func TestNopLogger(t *testing.T) {
+	var log Logger = NopLogger{}
+
+	// none of these should have any effect, Fatal included
+	log.Fatal("fatal")
+	log.Fatalf("%s", "fatalf")
+	log.Error("error")
+	log.Info("info")
+	log.Verbosef("%s", "verbosef")
+
+	// except Panic, which callers do not expect to return
+	if err, ok := recoverPanic(func() { log.Panicf("lost %s", "connection") }).(*PanicError); !ok || err.Message != "lost connection" {
+		t.Errorf("Expect Panicf to panic with the message, got %v", err)
+	}
+}
+
+func TestRecordingLogger_Panic(t *testing.T) {
+	log := &RecordingLogger{}
+
+	err, ok := recoverPanic(func() { log.With("request_id", 42).Panicw("lost connection", "attempt", 3) }).(*PanicError)
+	if !ok || err.Message != "lost connection" || !reflect.DeepEqual(err.Fields, []Field{{"request_id", 42}, {"attempt", 3}}) {
+		t.Errorf("Expect Panicw to panic with the message and fields, got %+v", err)
+	}
+
+	calls := log.Calls()
+	if len(calls) != 1 || calls[0].Method != "Panicw" || calls[0].Text() != "lost connection" {
+		t.Errorf("Expect the Panicw call to be recorded, got %+v", calls)
+	}
+}
+
+func TestRecordingLogger_Structured(t *testing.T) {
+	log := &RecordingLogger{}
+
+	log.With("request_id", 42).Infow("charged card", "amount", 100)
+	log.Errorw("failed")
+
+	calls := log.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("Expect %d calls to be recorded, got %d instead", 2, len(calls))
+	}
+
+	if calls[0].Method != "Infow" || calls[0].Text() != "charged card" {
+		t.Errorf("Expect an Infow call with text %q, got %+v instead", "charged card", calls[0])
+	}
+
+	if !reflect.DeepEqual(calls[0].Fields, []Field{{"request_id", 42}, {"amount", 100}}) {
+		t.Errorf("Expect the fields to be recorded, got %v instead", calls[0].Fields)
+	}
+
+	if calls[1].Method != "Errorw" || calls[1].Fields != nil {
+		t.Errorf("Expect an Errorw call without fields, got %+v instead", calls[1])
+	}
+}
@@ -0,0 +1,239 @@
+package golog
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// The level of loggers that have neither been setup nor have a configured
+// ancestor.
+const defaultLevel = DEBUG
+
+// The registry of module loggers. Every access to the map must hold
+// loggersLock, since GetLogger and Setup may be called from any goroutine.
+//
+// Module names are dot separated paths, e.g. "billing.invoice.pdf", which
+// form a tree: a logger that has not been setup inherits the level of its
+// nearest configured ancestor ("billing.invoice", then "billing").
+//
+// levelRules holds the levels set for the descendants of a module, e.g. by
+// the spec "billing.*=ERROR", which take precedence over the level of the
+// module itself; the empty name holds the level set for every module ("*").
+var (
+	loggersLock sync.RWMutex
+	loggers map[string]*logger = make(map[string]*logger)
+	levelRules map[string]Level = make(map[string]Level)
+)
+
+// The backend of the modules that have none of their own, set by the default
+// section of a configuration file like levelRules[""] for the level. Read on
+// every log, so it is kept in an atomic.Value holding a backendRule.
+var defaultBackendRule atomic.Value
+
+type backendRule struct {
+	backend Backend
+}
+
+// Sets the backend of every module that has none of its own, or reverts them
+// to defaultBackend if nil; must be called with loggersLock held for writing.
+func setBackendRule(backend Backend) {
+	defaultBackendRule.Store(backendRule{backend})
+}
+
+// Returns the backend of the modules that have none of their own.
+func ruleBackend() Backend {
+	if rule, ok := defaultBackendRule.Load().(backendRule); ok && rule.backend != nil {
+		return rule.backend
+	}
+
+	return defaultBackend()
+}
+
+// Returns the name of the parent module, and false if the module is at the
+// top of the tree.
+func parentName(name string) (string, bool) {
+	i := strings.LastIndex(name, ".")
+	if i < 0 {
+		return "", false
+	}
+
+	return name[:i], true
+}
+
+// Whether name is a (direct or indirect) descendant of ancestor. Every
+// module is a descendant of the empty name.
+func isDescendant(name, ancestor string) bool {
+	if ancestor == "" {
+		return name != ""
+	}
+
+	return strings.HasPrefix(name, ancestor + ".")
+}
+
+// Returns the level that an unconfigured logger of the given name inherits.
+// Must be called with loggersLock held.
+func inheritedLevel(name string) Level {
+	for parent, ok := parentName(name); ok; parent, ok = parentName(parent) {
+		if l, exists := levelRules[parent]; exists {
+			return l
+		}
+
+		if l, exists := loggers[parent]; exists && l.explicit {
+			return l.getConfig().Level
+		}
+	}
+
+	if l, exists := levelRules[""]; exists {
+		return l
+	}
+
+	return defaultLevel
+}
+
+// Re-applies the inherited level to every unconfigured descendant of name.
+// Must be called with loggersLock held for writing.
+func propagate(name string) {
+	for n, l := range loggers {
+		if !l.explicit && isDescendant(n, name) {
+			l.setLevel(inheritedLevel(n))
+		}
+	}
+}
+
+// Get a logger by name. If the logger has not been previously setup
+// the logger will be configured with the level of its nearest configured
+// ancestor, or the default level of "DEBUG" if there is none, and the
+// default prefix of "[$name] "
+func GetLogger(name string) Logger {
+	loggersLock.RLock()
+	l, ok := loggers[name]
+	loggersLock.RUnlock()
+
+	if ok {
+		return l
+	}
+
+	loggersLock.Lock()
+	defer loggersLock.Unlock()
+
+	// another goroutine may have created the logger while we were
+	// waiting for the write lock
+	if l, ok := loggers[name]; ok {
+		return l
+	}
+
+	l = newLogger(name, LogConfig{
+		Level: inheritedLevel(name),
+		Prefix: "[" + name + "] ",
+	})
+
+	loggers[name] = l
+	return l
+}
+
+// Sets up a logger by name, and with a set of log configurations. This
+// should be called at the start of the application, but is safe to call
+// concurrently with GetLogger. Loggers previously returned by GetLogger
+// for the same name pick up the new configuration, as do the descendants
+// of the module that have not been setup themselves.
+func Setup(name string, logConfig LogConfig) {
+	loggersLock.Lock()
+	defer loggersLock.Unlock()
+
+	if l, ok := loggers[name]; ok {
+		l.setConfig(logConfig)
+	} else {
+		l = newLogger(name, logConfig)
+		loggers[name] = l
+	}
+
+	loggers[name].explicit = true
+	propagate(name)
+}
+
+// Changes the level of a logger by name at runtime, keeping its prefix.
+// The new level is seen by every logger previously returned by GetLogger
+// for that name, and by the descendants of the module that have not been
+// setup themselves. If the logger has not been previously setup, it is
+// created with the default prefix of "[$name] ".
+func SetLevel(name string, l Level) {
+	loggersLock.Lock()
+	defer loggersLock.Unlock()
+
+	setLevel(name, l)
+}
+
+// Same as SetLevel; must be called with loggersLock held for writing.
+func setLevel(name string, l Level) {
+	if log, ok := loggers[name]; ok {
+		log.setLevel(l)
+	} else {
+		loggers[name] = newLogger(name, LogConfig{
+			Level: l,
+			Prefix: "[" + name + "] ",
+		})
+	}
+
+	loggers[name].explicit = true
+	propagate(name)
+}
+
+// Sets the level inherited by the unconfigured descendants of a module, or of
+// every unconfigured module if name is empty. Must be called with loggersLock
+// held for writing.
+func setLevelRule(name string, l Level) {
+	levelRules[name] = l
+	propagate(name)
+}
+
+// Sets the prefix of a module, keeping its level; must be called with
+// loggersLock held for writing. A logger that does not exist yet is created
+// with the level it inherits.
+func setPrefix(name string, prefix string) {
+	if log, ok := loggers[name]; ok {
+		log.setPrefix(prefix)
+		return
+	}
+
+	loggers[name] = newLogger(name, LogConfig{
+		Level: inheritedLevel(name),
+		Prefix: prefix,
+	})
+}
+
+// Sets the backend of a module, keeping its level and prefix; must be called
+// with loggersLock held for writing.
+func setBackend(name string, backend Backend) {
+	if log, ok := loggers[name]; ok {
+		log.setBackend(backend)
+		return
+	}
+
+	loggers[name] = newLogger(name, LogConfig{
+		Level: inheritedLevel(name),
+		Prefix: "[" + name + "] ",
+		Backend: backend,
+	})
+}
+
+// Reverts a module to the level it inherits, keeping its prefix and backend;
+// must be called with loggersLock held for writing.
+func unsetLevel(name string) {
+	log, ok := loggers[name]
+	if !ok {
+		return
+	}
+
+	log.explicit = false
+	log.setLevel(inheritedLevel(name))
+
+	propagate(name)
+}
+
+// Removes the level set for the descendants of a module (or for every module
+// if name is empty); must be called with loggersLock held for writing.
+func removeLevelRule(name string) {
+	delete(levelRules, name)
+	propagate(name)
+}
@@ -1,6 +1,8 @@
 package golog
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 )
 
@@ -8,12 +10,12 @@ import (
 func TestGetLogger(t *testing.T) {
 	// setup the loggers
 	loggers = map[string]*logger {
-		"A": newLogger(LogConfig {
+		"A": newLogger("A", LogConfig {
 			Prefix: "[A]",
 			Level: NOLOG,
 		}),
 
-		"B": newLogger(LogConfig {
+		"B": newLogger("B", LogConfig {
 			Prefix: "[B Logger]",
 			Level: INFO,
 		}),
@@ -22,7 +24,7 @@ func TestGetLogger(t *testing.T) {
 	// retrieve the test cases
 	testCases := []struct {
 		LoggerName string
-		Level level
+		Level Level
 		Prefix string
 	}{
 		{
@@ -43,7 +45,7 @@ func TestGetLogger(t *testing.T) {
 	}
 
 	for i, c := range testCases {
-		log := GetLogger(c.LoggerName)
+		log := GetLogger(c.LoggerName).(*logger)
 		if log.config.Level != c.Level {
 			t.Errorf("TC %d: Expected level to be %d, actual: %d",
 				i,
@@ -79,27 +81,412 @@ func TestSetup(t *testing.T) {
 	for i, c := range testCases {
 		if _, ok := loggers[c.Name]; !ok {
 			t.Errorf("TC %d: expected logger with name %s to exist",
-				c.Name,
 				i,
+				c.Name,
 			)
 		}
 
-		log := GetLogger(c.Name)
+		log := GetLogger(c.Name).(*logger)
 
 		if log.config.Prefix != c.Config.Prefix {
 			t.Errorf("TC %d: expected logger to have prefix %s, got %s instead",
+				i,
 				c.Config.Prefix,
 				log.config.Prefix,
-				i,
 			)
 		}
 
 		if log.config.Level != c.Config.Level {
 			t.Errorf("TC %d: expected logger to have level %d, got %d instead",
+				i,
 				c.Config.Level,
 				log.config.Level,
+			)
+		}
+	}
+}
+
+func TestGetLogger_Concurrent(t *testing.T) {
+	loggers = make(map[string]*logger)
+
+	const goroutines = 500
+	names := []string{"A", "B", "C", "D", "E"}
+
+	var wg sync.WaitGroup
+	results := make([]Logger, goroutines)
+
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = GetLogger(names[i%len(names)])
+		}(i)
+	}
+
+	wg.Wait()
+
+	// every goroutine asking for the same name must get the same logger
+	for i, l := range results {
+		expected := GetLogger(names[i%len(names)])
+		if l != expected {
+			t.Errorf("TC %d: expected the same logger for name %s",
+				i,
+				names[i%len(names)],
+			)
+		}
+	}
+
+	if len(loggers) != len(names) {
+		t.Errorf("Expected %d loggers to be registered, got %d instead",
+			len(names),
+			len(loggers),
+		)
+	}
+}
+
+func TestSetup_Concurrent(t *testing.T) {
+	loggers = make(map[string]*logger)
+
+	const goroutines = 500
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(2)
+
+		name := fmt.Sprintf("module-%d", i%50)
+		go func(i int) {
+			defer wg.Done()
+			Setup(name, LogConfig{
+				Prefix: "[" + name + "] ",
+				Level: INFO,
+			})
+		}(i)
+
+		go func(i int) {
+			defer wg.Done()
+			log := GetLogger(name)
+			log.Verbose("should not be logged")
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i := 0; i < 50; i++ {
+		name := fmt.Sprintf("module-%d", i)
+		Setup(name, LogConfig{
+			Prefix: "[" + name + "] ",
+			Level: ERROR,
+		})
+
+		log := GetLogger(name).(*logger)
+		if log.config.Level != ERROR {
+			t.Errorf("TC %d: expected logger %s to have level %d, got %d instead",
+				i,
+				name,
+				ERROR,
+				log.config.Level,
+			)
+		}
+
+		if log.config.Prefix != "["+name+"] " {
+			t.Errorf("TC %d: expected logger %s to have prefix %s, got %s instead",
 				i,
+				name,
+				"["+name+"] ",
+				log.config.Prefix,
 			)
 		}
 	}
+
+	if len(loggers) != 50 {
+		t.Errorf("Expected %d loggers to be registered, got %d instead",
+			50,
+			len(loggers),
+		)
+	}
+}
+
+func TestSetup_ExistingHandles(t *testing.T) {
+	loggers = make(map[string]*logger)
+
+	log := GetLogger("A").(*logger)
+	Setup("A", LogConfig{
+		Prefix: "[A Logger]",
+		Level: WARN,
+	})
+
+	if GetLogger("A") != Logger(log) {
+		t.Error("Expect Setup to keep the existing logger for the name.")
+	}
+
+	if log.getConfig().Level != WARN {
+		t.Errorf("Expect existing handle to have level %d, got %d instead",
+			WARN,
+			log.getConfig().Level,
+		)
+	}
+
+	if log.getConfig().Prefix != "[A Logger]" {
+		t.Errorf("Expect existing handle to have prefix %s, got %s instead",
+			"[A Logger]",
+			log.getConfig().Prefix,
+		)
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	loggers = make(map[string]*logger)
+
+	Setup("A", LogConfig{
+		Prefix: "[A Logger]",
+		Level: INFO,
+	})
+
+	// retrieve the test cases
+	testCases := []struct {
+		LoggerName string
+		Level Level
+		Prefix string
+	}{
+		{
+			LoggerName: "A",
+			Level: DEBUG,
+			Prefix: "[A Logger]",
+		},
+		{
+			LoggerName: "A",
+			Level: NOLOG,
+			Prefix: "[A Logger]",
+		},
+		{
+			LoggerName: "B",
+			Level: VERBOSE,
+			Prefix: "[B] ",
+		},
+	}
+
+	for i, c := range testCases {
+		log := GetLogger(c.LoggerName).(*logger)
+		SetLevel(c.LoggerName, c.Level)
+
+		if log != GetLogger(c.LoggerName) {
+			t.Errorf("TC %d: Expect SetLevel to keep the existing logger for %s",
+				i,
+				c.LoggerName,
+			)
+		}
+
+		config := log.getConfig()
+		if config.Level != c.Level {
+			t.Errorf("TC %d: Expected level to be %d, actual: %d",
+				i,
+				c.Level,
+				config.Level,
+			)
+		}
+
+		if config.Prefix != c.Prefix {
+			t.Errorf("TC %d: Expected prefix to be %s, actual: %s",
+				i,
+				c.Prefix,
+				config.Prefix,
+			)
+		}
+	}
+
+	// loggers that do not exist yet are created with the new level
+	SetLevel("C", ERROR)
+	log := GetLogger("C").(*logger)
+	if log.getConfig().Level != ERROR {
+		t.Errorf("Expected level to be %d, actual: %d",
+			ERROR,
+			log.getConfig().Level,
+		)
+	}
+}
+
+func TestSetLevel_Concurrent(t *testing.T) {
+	loggers = make(map[string]*logger)
+
+	log, mockBackend := newLoggerWithMocks(LogConfig{
+		Prefix: "[A]",
+		Level: NOLOG,
+	})
+	loggers["A"] = log
+
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			SetLevel("A", WARN)
+		}()
+
+		go func() {
+			defer wg.Done()
+			GetLogger("A").Verbose("never logged")
+		}()
+	}
+
+	wg.Wait()
+
+	GetLogger("A").Warn("hello")
+	if len(mockBackend.Records) != 1 || mockBackend.Last().Level != WARN {
+		t.Errorf("Expect a single %s record, got %+v instead",
+			WARN,
+			mockBackend.Records,
+		)
+	}
+}
+
+func TestGetLogger_Inherited(t *testing.T) {
+	loggers = make(map[string]*logger)
+
+	Setup("billing", LogConfig{
+		Prefix: "[billing]",
+		Level: ERROR,
+	})
+
+	Setup("billing.invoice", LogConfig{
+		Prefix: "[invoice]",
+		Level: WARN,
+	})
+
+	// an unconfigured logger in the middle of the tree should not be
+	// inherited from
+	GetLogger("billing.refund")
+
+	testCases := []struct {
+		LoggerName string
+		Level Level
+		Prefix string
+	}{
+		{
+			LoggerName: "billing.invoice.pdf",
+			Level: WARN,
+			Prefix: "[billing.invoice.pdf] ",
+		},
+		{
+			LoggerName: "billing.refund.card",
+			Level: ERROR,
+			Prefix: "[billing.refund.card] ",
+		},
+		{
+			LoggerName: "billing.refund",
+			Level: ERROR,
+			Prefix: "[billing.refund] ",
+		},
+		{
+			LoggerName: "billingual",
+			Level: DEBUG,
+			Prefix: "[billingual] ",
+		},
+		{
+			LoggerName: "shipping.label",
+			Level: DEBUG,
+			Prefix: "[shipping.label] ",
+		},
+	}
+
+	for i, c := range testCases {
+		config := GetLogger(c.LoggerName).(*logger).getConfig()
+		if config.Level != c.Level {
+			t.Errorf("TC %d: Expected level of %s to be %d, actual: %d",
+				i,
+				c.LoggerName,
+				c.Level,
+				config.Level,
+			)
+		}
+
+		if config.Prefix != c.Prefix {
+			t.Errorf("TC %d: Expected prefix of %s to be %s, actual: %s",
+				i,
+				c.LoggerName,
+				c.Prefix,
+				config.Prefix,
+			)
+		}
+	}
+}
+
+func TestSetup_Retroactive(t *testing.T) {
+	loggers = make(map[string]*logger)
+
+	pdf := GetLogger("billing.invoice.pdf").(*logger)
+	invoice := GetLogger("billing.invoice").(*logger)
+	refund := GetLogger("billing.refund").(*logger)
+	shipping := GetLogger("shipping").(*logger)
+
+	Setup("billing.refund", LogConfig{
+		Prefix: "[refund]",
+		Level: VERBOSE,
+	})
+
+	Setup("billing", LogConfig{
+		Prefix: "[billing]",
+		Level: WARN,
+	})
+
+	testCases := []struct {
+		Logger *logger
+		Level Level
+	}{
+		{pdf, WARN},
+		{invoice, WARN},
+		{refund, VERBOSE},
+		{shipping, DEBUG},
+	}
+
+	for i, c := range testCases {
+		if c.Logger.getConfig().Level != c.Level {
+			t.Errorf("TC %d: Expected level to be %d, actual: %d",
+				i,
+				c.Level,
+				c.Logger.getConfig().Level,
+			)
+		}
+	}
+
+	// changing the level of an ancestor at runtime reaches the descendants,
+	// except those that have been explicitly configured
+	SetLevel("billing.invoice", ERROR)
+	SetLevel("billing", INFO)
+
+	if pdf.getConfig().Level != ERROR {
+		t.Errorf("Expected level to be %d, actual: %d",
+			ERROR,
+			pdf.getConfig().Level,
+		)
+	}
+
+	if invoice.getConfig().Level != ERROR {
+		t.Errorf("Expected level to be %d, actual: %d",
+			ERROR,
+			invoice.getConfig().Level,
+		)
+	}
+
+	if refund.getConfig().Level != VERBOSE {
+		t.Errorf("Expected level to be %d, actual: %d",
+			VERBOSE,
+			refund.getConfig().Level,
+		)
+	}
+}
+
+// Empties the registry, now and once the test is over.
+func resetRegistry(t *testing.T) {
+	reset := func() {
+		loggersLock.Lock()
+		defer loggersLock.Unlock()
+
+		loggers = make(map[string]*logger)
+		levelRules = make(map[string]Level)
+		setBackendRule(nil)
+	}
+
+	reset()
+	t.Cleanup(reset)
 }
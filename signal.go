@@ -0,0 +1,81 @@
+package golog
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// Reads a logging configuration and applies it to the module loggers, e.g.
+// on SIGHUP (see ReloadOnSIGHUP). An error means nothing was applied.
+type ConfigSource func() error
+
+// Returns a source that reads the GOLOG_* environment variables, see
+// SetupFromEnv.
+func EnvSource() ConfigSource {
+	return SetupFromEnv
+}
+
+// Returns a source that reads a configuration file, see LoadConfigFile. The
+// file is applied every time, even if it did not change, so that levels
+// changed since e.g. through SetLevel are reset. Settings removed from the
+// file since it was last read revert to the defaults of GetLogger.
+func FileSource(path string) ConfigSource {
+	file := &configFile{path: path}
+	return func() error {
+		return file.load(true)
+	}
+}
+
+// Returns a source that applies a spec string, see SetupFromSpec.
+func SpecSource(spec string) ConfigSource {
+	return func() error {
+		return SetupFromSpec(spec)
+	}
+}
+
+// Reads the source every time the process receives SIGHUP, and applies it to
+// the module loggers. Each source applies its configuration at once, so that
+// logs issued during a reload see either the previous or the new levels.
+//
+// A source that cannot be read or is invalid is reported as an ERROR to the
+// "golog" module, and the previous configuration is kept. The source is not
+// read until the first signal; call it directly to load it at startup.
+//
+// Returns a function that uninstalls the handler.
+func ReloadOnSIGHUP(source ConfigSource) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		for {
+			select {
+			case <-done:
+				return
+
+			case <-signals:
+				if err := source(); err != nil {
+					GetLogger(internalModule).Errorf("keeping the previous logging configuration: %s", err)
+				} else {
+					GetLogger(internalModule).Info("reloaded logging configuration on SIGHUP")
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+
+		<-stopped
+	}
+}
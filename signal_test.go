@@ -0,0 +1,171 @@
+package golog
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// Sends SIGHUP to the test process.
+func sendSIGHUP(t *testing.T) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGHUP cannot be sent on windows")
+	}
+
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatalf("Unable to find the test process: %s", err)
+	}
+
+	if err := process.Signal(syscall.SIGHUP); err != nil {
+		t.Fatalf("Unable to send SIGHUP: %s", err)
+	}
+}
+
+// Waits for the level of a logger to change to l, since the signal is
+// handled asynchronously.
+func waitForLevel(t *testing.T, log *logger, l Level) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for log.getConfig().Level != l {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expect level %s after SIGHUP, got %s instead",
+				l,
+				log.getConfig().Level,
+			)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReloadOnSIGHUP_Env(t *testing.T) {
+	resetRegistry(t)
+
+	t.Setenv("GOLOG_LEVEL_DB", "INFO")
+	stop := ReloadOnSIGHUP(EnvSource())
+	defer stop()
+
+	db := GetLogger("db").(*logger)
+	if db.getConfig().Level != DEBUG {
+		t.Fatalf("Expect the source not to be read before SIGHUP, got level %s",
+			db.getConfig().Level,
+		)
+	}
+
+	sendSIGHUP(t)
+	waitForLevel(t, db, INFO)
+
+	t.Setenv("GOLOG_LEVEL_DB", "ERROR")
+	sendSIGHUP(t)
+	waitForLevel(t, db, ERROR)
+}
+
+func TestReloadOnSIGHUP_File(t *testing.T) {
+	resetRegistry(t)
+
+	path := filepath.Join(t.TempDir(), "logging.yaml")
+	writeConfig(t, path, `
+modules:
+  db:
+    level: WARN
+    prefix: "[database] "
+`)
+
+	source := FileSource(path)
+	if err := source(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	stop := ReloadOnSIGHUP(source)
+	defer stop()
+
+	db := GetLogger("db").(*logger)
+	writeConfig(t, path, `
+modules:
+  db:
+    level: VERBOSE
+`)
+
+	sendSIGHUP(t)
+	waitForLevel(t, db, VERBOSE)
+
+	if db.getConfig().Prefix != "[db] " {
+		t.Errorf("Expect the removed prefix to revert to %q, got %q instead",
+			"[db] ",
+			db.getConfig().Prefix,
+		)
+	}
+}
+
+func TestReloadOnSIGHUP_UnchangedFile(t *testing.T) {
+	resetRegistry(t)
+
+	path := filepath.Join(t.TempDir(), "logging.yaml")
+	writeConfig(t, path, "modules:\n  db:\n    level: INFO\n")
+
+	source := FileSource(path)
+	if err := source(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	stop := ReloadOnSIGHUP(source)
+	defer stop()
+
+	db := GetLogger("db").(*logger)
+	SetLevel("db", VERBOSE)
+
+	// the file is applied again even though it did not change
+	sendSIGHUP(t)
+	waitForLevel(t, db, INFO)
+}
+
+// Hands records over to the test, which may run on another goroutine than
+// the one logging.
+type chanBackend chan Record
+
+func (backend chanBackend) Log(depth int, record Record) {
+	backend <- record
+}
+
+func TestReloadOnSIGHUP_Invalid(t *testing.T) {
+	resetRegistry(t)
+
+	internal := make(chanBackend, 1)
+	Setup(internalModule, LogConfig{
+		Level: INFO,
+		Backend: internal,
+	})
+
+	Setup("db", LogConfig{Level: WARN})
+	db := GetLogger("db").(*logger)
+
+	stop := ReloadOnSIGHUP(SpecSource("db=LOUD"))
+	defer stop()
+
+	sendSIGHUP(t)
+
+	select {
+	case record := <-internal:
+		if record.Level != ERROR || !strings.Contains(record.Message, `unknown level "LOUD"`) {
+			t.Errorf("Expect the invalid source to be reported, got %+v", record)
+		}
+
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expect the invalid source to be reported after SIGHUP.")
+	}
+
+	if db.getConfig().Level != WARN {
+		t.Errorf("Expect an invalid source to keep level %s, got %s instead",
+			WARN,
+			db.getConfig().Level,
+		)
+	}
+}
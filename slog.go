@@ -0,0 +1,233 @@
+package golog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+)
+
+// The number of stack frames between SlogHandler.Handle and the application
+// code calling a method of slog.Logger, or one of the top-level functions of
+// log/slog: Handle is called by slog.Logger.log, called by e.g.
+// slog.Logger.Info.
+const slogCallerDepth = 3
+
+// The number of stack frames between SlogHandler.Enabled and the application
+// code: Enabled is called by slog.Logger.Enabled, called by slog.Logger.log.
+const slogEnabledDepth = slogCallerDepth + 1
+
+// The slog level FATAL records are passed on with, above slog.LevelError.
+const slogLevelFatal = slog.LevelError + 4
+
+// Returns the golog level matching a slog level: levels below slog.LevelDebug
+// map onto VERBOSE, and levels in between round down, e.g. slog.LevelInfo + 2
+// is INFO.
+func levelFromSlog(l slog.Level) Level {
+	switch {
+	case l >= slog.LevelError:
+		return ERROR
+
+	case l >= slog.LevelWarn:
+		return WARN
+
+	case l >= slog.LevelInfo:
+		return INFO
+
+	case l >= slog.LevelDebug:
+		return DEBUG
+	}
+
+	return VERBOSE
+}
+
+// Returns the slog level matching a golog level; VERBOSE is slog.LevelDebug - 4
+// and FATAL slog.LevelError + 4.
+func levelToSlog(l Level) slog.Level {
+	switch l {
+	case FATAL:
+		return slogLevelFatal
+
+	case ERROR:
+		return slog.LevelError
+
+	case WARN:
+		return slog.LevelWarn
+
+	case INFO:
+		return slog.LevelInfo
+
+	case DEBUG:
+		return slog.LevelDebug
+	}
+
+	return slog.LevelDebug - 4
+}
+
+// A slog.Handler writing records into a golog module, so that code using
+// log/slog follows the level, prefix and backend of the module, e.g.
+//
+//	logger := slog.New(golog.NewSlogHandler("billing"))
+//	logger.Info("charged card", "amount", 100)
+//
+// slog levels map onto golog ones, rounding down: slog.LevelError and above
+// is ERROR, slog.LevelWarn WARN, slog.LevelInfo INFO, slog.LevelDebug DEBUG,
+// and anything below VERBOSE. Records pass if the level of the module, or
+// glog's verbosity (see HonorGlogVerbosity), allows the level they map onto.
+// Attributes map onto fields, with the keys of groups joined by dots, e.g.
+// "request.id".
+//
+// The location of the log reported to the backend is the caller of the
+// slog.Logger method; code wrapping slog.Logger is reported instead of its
+// callers.
+type SlogHandler struct {
+	log *logger
+
+	// the fields attached through WithAttrs
+	fields []Field
+
+	// the prefix of the keys of attributes, e.g. "request." within the
+	// group "request"
+	group string
+}
+
+// Returns a handler writing into the module, see GetLogger.
+func NewSlogHandler(module string) *SlogHandler {
+	return &SlogHandler{
+		log: GetLogger(module).(*logger),
+	}
+}
+
+// Reports whether the level of the module, or glog's verbosity (see
+// HonorGlogVerbosity), allows records of level l. Since
+// slog also calls Enabled merely to check the level, e.g. for
+// slog.Logger.Enabled, the records it then drops are not counted as
+// suppressed (see PublishExpvar); records handed over to Handle are counted
+// as emitted.
+func (handler *SlogHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return handler.log.getConfig().allows(slogEnabledDepth, levelFromSlog(l))
+}
+
+func (handler *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	config := handler.log.getConfig()
+	l := levelFromSlog(r.Level)
+
+	fields := handler.fields
+	if r.NumAttrs() > 0 {
+		fields = append([]Field(nil), handler.fields...)
+		r.Attrs(func(attr slog.Attr) bool {
+			fields = appendAttr(fields, handler.group, attr)
+			return true
+		})
+	}
+
+	handler.log.counts.add(l, true)
+	config.backend().Log(slogCallerDepth, Record{
+		Time: r.Time,
+		Level: l,
+		Module: handler.log.name,
+		Prefix: config.Prefix,
+		Message: r.Message,
+		Fields: fields,
+	})
+
+	return nil
+}
+
+func (handler *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := append([]Field(nil), handler.fields...)
+	for _, attr := range attrs {
+		fields = appendAttr(fields, handler.group, attr)
+	}
+
+	return &SlogHandler{
+		log: handler.log,
+		fields: fields,
+		group: handler.group,
+	}
+}
+
+func (handler *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return handler
+	}
+
+	return &SlogHandler{
+		log: handler.log,
+		fields: handler.fields,
+		group: handler.group + name + ".",
+	}
+}
+
+// Appends an attribute as fields, prefixing its key with the group. Groups
+// are flattened, and empty attributes left out, as slog.Handler requires.
+func appendAttr(fields []Field, group string, attr slog.Attr) []Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			group += attr.Key + "."
+		}
+
+		for _, member := range attr.Value.Group() {
+			fields = appendAttr(fields, group, member)
+		}
+
+		return fields
+	}
+
+	return append(fields, Field{
+		Key: group + attr.Key,
+		Value: attr.Value.Any(),
+	})
+}
+
+var _ slog.Handler = (*SlogHandler)(nil)
+
+// A Backend writing records to a slog.Handler, so that golog modules can be
+// written out by any slog handler, e.g.
+//
+//	golog.Setup("billing", golog.LogConfig{
+//		Level: golog.INFO,
+//		Backend: golog.NewSlogBackend(slog.NewJSONHandler(os.Stderr, nil)),
+//	})
+//
+// The record keeps its message, and gets the attribute "module" followed by
+// its fields; the prefix is left out. FATAL records are passed on at
+// slog.LevelError + 4, and VERBOSE ones at slog.LevelDebug - 4. The location of
+// the log is passed on, for slog.HandlerOptions.AddSource.
+type SlogBackend struct {
+	handler slog.Handler
+}
+
+// Returns a backend writing to the handler.
+func NewSlogBackend(handler slog.Handler) *SlogBackend {
+	return &SlogBackend{
+		handler: handler,
+	}
+}
+
+func (backend *SlogBackend) Log(depth int, record Record) {
+	ctx := context.Background()
+
+	l := levelToSlog(record.Level)
+	if !backend.handler.Enabled(ctx, l) {
+		return
+	}
+
+	// skip runtime.Callers and Log
+	var pcs [1]uintptr
+	runtime.Callers(depth + 2, pcs[:])
+
+	r := slog.NewRecord(record.Time, l, record.Message, pcs[0])
+	r.AddAttrs(slog.String("module", record.Module))
+	for _, field := range record.Fields {
+		r.AddAttrs(slog.Any(field.Key, field.Value))
+	}
+
+	backend.handler.Handle(ctx, r)
+}
+
+var _ Backend = (*SlogBackend)(nil)
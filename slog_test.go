@@ -0,0 +1,160 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSlogHandler(t *testing.T) {
+	resetRegistry(t)
+
+	mockBackend := &MockBackend{}
+	Setup("billing", LogConfig{
+		Level: DEBUG,
+		Prefix: "[billing] ",
+		Backend: mockBackend,
+	})
+
+	slogger := slog.New(NewSlogHandler("billing"))
+
+	testCases := []struct {
+		Level slog.Level
+		Expected Level
+	}{
+		{slog.LevelError + 4, ERROR},
+		{slog.LevelError, ERROR},
+		{slog.LevelWarn, WARN},
+		{slog.LevelInfo + 2, INFO},
+		{slog.LevelInfo, INFO},
+		{slog.LevelDebug, DEBUG},
+	}
+
+	for i, c := range testCases {
+		slogger.Log(context.Background(), c.Level, "charged card")
+
+		record := mockBackend.Last()
+		if len(mockBackend.Records) != i + 1 || record.Level != c.Expected || record.Module != "billing" ||
+			record.Prefix != "[billing] " || record.Message != "charged card" {
+			t.Errorf("TC %d: Expect a %s record, got %+v instead", i, c.Expected, record)
+		}
+	}
+
+	// below the level of the module
+	slogger.Log(context.Background(), slog.LevelDebug - 4, "suppressed")
+	if len(mockBackend.Records) != len(testCases) {
+		t.Errorf("Expect VERBOSE records to be suppressed, got %+v", mockBackend.Last())
+	}
+
+	// level checks are not counted as suppressed logs
+	slogger.Enabled(context.Background(), slog.LevelDebug - 4)
+	if _, suppressed := GetLogger("billing").(*logger).counts.get(VERBOSE); suppressed != 0 {
+		t.Errorf("Expect level checks not to be counted, got %d suppressed", suppressed)
+	}
+
+	if emitted, _ := GetLogger("billing").(*logger).counts.get(DEBUG); emitted != 1 {
+		t.Errorf("Expect the emitted record to be counted, got %d", emitted)
+	}
+}
+
+func TestSlogHandler_Attrs(t *testing.T) {
+	resetRegistry(t)
+
+	mockBackend := &MockBackend{}
+	Setup("billing", LogConfig{Level: INFO, Backend: mockBackend})
+
+	slogger := slog.New(NewSlogHandler("billing")).With("service", "api").WithGroup("request").With("id", 42)
+	_, _, line, _ := runtime.Caller(0)
+	slogger.Info("charged card",
+		"amount", 100,
+		slog.Group("card", "brand", "visa"),
+		slog.Group("", "inlined", true),
+		slog.Attr{},
+	)
+
+	expected := []Field{
+		{"service", "api"},
+		{"request.id", int64(42)},
+		{"request.amount", int64(100)},
+		{"request.card.brand", "visa"},
+		{"request.inlined", true},
+	}
+
+	if !reflect.DeepEqual(mockBackend.Last().Fields, expected) {
+		t.Errorf("Expect fields %v, got %v instead", expected, mockBackend.Last().Fields)
+	}
+
+	// the location of the call to Info
+	if filepath.Base(mockBackend.File) != "slog_test.go" || mockBackend.Line != line + 1 {
+		t.Errorf("Expect the record to be attributed to slog_test.go:%d, got %s:%d",
+			line + 1,
+			mockBackend.File,
+			mockBackend.Line,
+		)
+	}
+}
+
+func TestSlogBackend(t *testing.T) {
+	mockNow(t)
+	exitCode := mockExit(t)
+
+	var output bytes.Buffer
+	handler := slog.NewJSONHandler(&output, &slog.HandlerOptions{
+		AddSource: true,
+		Level: slog.LevelDebug - 4,
+	})
+
+	log := newLogger("billing", LogConfig{
+		Level: VERBOSE,
+		Prefix: "[billing] ",
+		Backend: NewSlogBackend(handler),
+	})
+
+	testCases := []struct {
+		Log func()
+		Level string
+	}{
+		{func() { log.Fatal("charged card") }, "ERROR+4"},
+		{func() { log.Error("charged card") }, "ERROR"},
+		{func() { log.With("request_id", 42).Warnw("charged card", "amount", 100) }, "WARN"},
+		{func() { log.Infof("charged %s", "card") }, "INFO"},
+		{func() { log.Debug("charged card") }, "DEBUG"},
+		{func() { log.Verbose("charged card") }, "DEBUG-4"},
+	}
+
+	for i, c := range testCases {
+		output.Reset()
+		c.Log()
+
+		var entry map[string]interface{}
+		if err := json.Unmarshal(output.Bytes(), &entry); err != nil {
+			t.Fatalf("TC %d: Unexpected error: %s", i, err)
+		}
+
+		if entry["level"] != c.Level || entry["msg"] != "charged card" || entry["module"] != "billing" ||
+			entry["time"] != "2017-03-01T10:00:00Z" {
+			t.Errorf("TC %d: Expect a %s entry, got %v instead", i, c.Level, entry)
+		}
+
+		source, _ := entry["source"].(map[string]interface{})
+		if file, _ := source["file"].(string); !strings.HasSuffix(file, "slog_test.go") {
+			t.Errorf("TC %d: Expect the source to be the test, got %v", i, source)
+		}
+	}
+
+	if *exitCode != 255 {
+		t.Errorf("Expect Fatal to exit with code 255, got %d instead", *exitCode)
+	}
+
+	output.Reset()
+	log.With("request_id", 42).Infow("charged card", "amount", 100)
+	if !strings.Contains(output.String(), `"module":"billing","request_id":42,"amount":100}`) {
+		t.Errorf("Expect the fields as attributes, got %s", output.String())
+	}
+}
@@ -0,0 +1,130 @@
+package golog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Represents a single "pattern=LEVEL" entry of a spec.
+type specEntry struct {
+	// the module name, the module name followed by ".*", or "*"
+	pattern string
+
+	level Level
+}
+
+// Parses a comma separated list of "pattern=LEVEL" entries, e.g.
+//
+//	db=DEBUG,http=WARN,billing.*=ERROR,*=INFO
+//
+// Returns an error describing the first malformed entry.
+func parseSpec(spec string) ([]specEntry, error) {
+	var entries []specEntry
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		i := strings.Index(entry, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("golog: invalid spec entry %q: expected pattern=LEVEL", entry)
+		}
+
+		pattern := strings.TrimSpace(entry[:i])
+		levelName := strings.TrimSpace(entry[i + 1:])
+
+		name := strings.TrimSuffix(pattern, ".*")
+		if pattern == "" || name == "" || (strings.Contains(name, "*") && pattern != "*") {
+			return nil, fmt.Errorf("golog: invalid spec entry %q: invalid pattern %q; expected a module name, name.* or *",
+				entry,
+				pattern,
+			)
+		}
+
+		l, ok := GetLevel(levelName)
+		if !ok {
+			return nil, fmt.Errorf("golog: invalid spec entry %q: unknown level %q", entry, levelName)
+		}
+
+		entries = append(entries, specEntry{
+			pattern: pattern,
+			level: l,
+		})
+	}
+
+	return entries, nil
+}
+
+// Applies the entries in order; must be called with loggersLock held for
+// writing.
+func applySpec(entries []specEntry) {
+	for _, entry := range entries {
+		switch {
+		case entry.pattern == "*":
+			setLevelRule("", entry.level)
+
+		case strings.HasSuffix(entry.pattern, ".*"):
+			setLevelRule(strings.TrimSuffix(entry.pattern, ".*"), entry.level)
+
+		default:
+			setLevel(entry.pattern, entry.level)
+		}
+	}
+}
+
+// Configures the levels of the module loggers from a spec, similar to glog's
+// -vmodule flag, e.g.
+//
+//	golog.SetupFromSpec("db=DEBUG,http=WARN,billing.*=ERROR,*=INFO")
+//
+// where
+//
+//	name=LEVEL sets the level of the module, as SetLevel does
+//	name.*=LEVEL sets the level of the descendants of the module that do not
+//	have a level of their own
+//	*=LEVEL sets the level of every module that does not have a level of its
+//	own, in place of the default "DEBUG"
+//
+// Entries are applied in order, and only if the whole spec is valid; the
+// error describes the first malformed entry otherwise.
+func SetupFromSpec(spec string) error {
+	entries, err := parseSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	loggersLock.Lock()
+	defer loggersLock.Unlock()
+
+	applySpec(entries)
+	return nil
+}
+
+// A flag.Value configuring the module loggers from a spec, e.g.
+//
+//	flag.Var(&golog.SpecFlag{}, "log-levels", "module log levels, e.g. db=DEBUG,*=INFO")
+//
+// Each occurrence of the flag is applied through SetupFromSpec.
+type SpecFlag struct {
+	specs []string
+}
+
+// Returns the specs applied so far, joined by commas.
+func (flag *SpecFlag) String() string {
+	if flag == nil {
+		return ""
+	}
+
+	return strings.Join(flag.specs, ",")
+}
+
+func (flag *SpecFlag) Set(spec string) error {
+	if err := SetupFromSpec(spec); err != nil {
+		return err
+	}
+
+	flag.specs = append(flag.specs, spec)
+	return nil
+}
@@ -0,0 +1,139 @@
+package golog
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSetupFromSpec(t *testing.T) {
+	resetRegistry(t)
+
+	Setup("billing.invoice", LogConfig{
+		Prefix: "[invoice]",
+		Level: VERBOSE,
+	})
+
+	db := GetLogger("db").(*logger)
+	pdf := GetLogger("billing.invoice.pdf").(*logger)
+	refund := GetLogger("billing.refund").(*logger)
+	cache := GetLogger("cache").(*logger)
+
+	err := SetupFromSpec(" db=DEBUG, http=warn,billing.*=ERROR,*=INFO,")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	testCases := []struct {
+		LoggerName string
+		Level Level
+		Prefix string
+	}{
+		{"db", DEBUG, "[db] "},
+		{"http", WARN, "[http] "},
+		{"billing", INFO, "[billing] "},
+		{"billing.refund", ERROR, "[billing.refund] "},
+		{"billing.refund.card", ERROR, "[billing.refund.card] "},
+		{"billing.invoice", VERBOSE, "[invoice]"},
+		{"billing.invoice.pdf", VERBOSE, "[billing.invoice.pdf] "},
+		{"cache", INFO, "[cache] "},
+		{"new.module", INFO, "[new.module] "},
+	}
+
+	for i, c := range testCases {
+		config := GetLogger(c.LoggerName).(*logger).getConfig()
+		if config.Level != c.Level {
+			t.Errorf("TC %d: Expected level of %s to be %s, actual: %s",
+				i,
+				c.LoggerName,
+				c.Level,
+				config.Level,
+			)
+		}
+
+		if config.Prefix != c.Prefix {
+			t.Errorf("TC %d: Expected prefix of %s to be %s, actual: %s",
+				i,
+				c.LoggerName,
+				c.Prefix,
+				config.Prefix,
+			)
+		}
+	}
+
+	// existing handles see the new levels
+	if db.getConfig().Level != DEBUG || pdf.getConfig().Level != VERBOSE ||
+		refund.getConfig().Level != ERROR || cache.getConfig().Level != INFO {
+		t.Error("Expect existing loggers to pick up the levels of the spec.")
+	}
+}
+
+func TestSetupFromSpec_Errors(t *testing.T) {
+	resetRegistry(t)
+
+	testCases := []struct {
+		Spec string
+		Error string
+	}{
+		{"db", `invalid spec entry "db": expected pattern=LEVEL`},
+		{"db=LOUD", `invalid spec entry "db=LOUD": unknown level "LOUD"`},
+		{"db=", `invalid spec entry "db=": unknown level ""`},
+		{"=INFO", `invalid spec entry "=INFO": invalid pattern ""`},
+		{".*=INFO", `invalid spec entry ".*=INFO": invalid pattern ".*"`},
+		{"bill*ing=INFO", `invalid spec entry "bill*ing=INFO": invalid pattern "bill*ing"`},
+		{"*.*=INFO", `invalid spec entry "*.*=INFO": invalid pattern "*.*"`},
+		{"http=INFO,db=LOUD", `invalid spec entry "db=LOUD": unknown level "LOUD"`},
+	}
+
+	for i, c := range testCases {
+		err := SetupFromSpec(c.Spec)
+		if err == nil {
+			t.Errorf("TC %d: Expected an error for spec %q", i, c.Spec)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), c.Error) {
+			t.Errorf("TC %d: Expected error to contain %q, actual: %q",
+				i,
+				c.Error,
+				err.Error(),
+			)
+		}
+	}
+
+	// nothing is applied unless the whole spec is valid
+	if len(loggers) != 0 {
+		t.Errorf("Expected no logger to be configured, got %d", len(loggers))
+	}
+}
+
+func TestSpecFlag(t *testing.T) {
+	resetRegistry(t)
+
+	spec := &SpecFlag{}
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.Var(spec, "log-levels", "module log levels")
+
+	err := flags.Parse([]string{"-log-levels", "db=DEBUG", "-log-levels", "*=WARN"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if GetLogger("db").(*logger).getConfig().Level != DEBUG {
+		t.Error("Expect the db logger to be set to DEBUG.")
+	}
+
+	if GetLogger("http").(*logger).getConfig().Level != WARN {
+		t.Error("Expect the http logger to be set to WARN.")
+	}
+
+	if spec.String() != "db=DEBUG,*=WARN" {
+		t.Errorf("Expected the flag to print as %s, actual: %s", "db=DEBUG,*=WARN", spec.String())
+	}
+
+	if err := flags.Parse([]string{"-log-levels", "db=LOUD"}); err == nil {
+		t.Error("Expect an error for an unknown level.")
+	}
+}
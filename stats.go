@@ -0,0 +1,114 @@
+package golog
+
+import (
+	"expvar"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// The levels a message can be logged at, from Fatal to Verbose.
+var logLevels = []Level{FATAL, ERROR, WARN, INFO, DEBUG, VERBOSE}
+
+// Counts the logs of a module that were emitted, or suppressed by the level
+// check, per level. Safe for concurrent use.
+type levelCounts struct {
+	emitted [VERBOSE + 1]int64
+	suppressed [VERBOSE + 1]int64
+}
+
+func (counts *levelCounts) add(l Level, emitted bool) {
+	if l < FATAL || l > VERBOSE {
+		return
+	}
+
+	if emitted {
+		atomic.AddInt64(&counts.emitted[l], 1)
+	} else {
+		atomic.AddInt64(&counts.suppressed[l], 1)
+	}
+}
+
+// Returns the number of logs emitted and suppressed at level l.
+func (counts *levelCounts) get(l Level) (emitted int64, suppressed int64) {
+	return atomic.LoadInt64(&counts.emitted[l]), atomic.LoadInt64(&counts.suppressed[l])
+}
+
+// A snapshot of a module logger and its counts.
+type moduleStats struct {
+	module string
+	level Level
+	prefix string
+
+	emitted map[Level]int64
+	suppressed map[Level]int64
+}
+
+// Returns a snapshot of every registered module, sorted by name.
+func registryStats() []moduleStats {
+	loggersLock.RLock()
+	defer loggersLock.RUnlock()
+
+	stats := make([]moduleStats, 0, len(loggers))
+	for name, log := range loggers {
+		config := log.getConfig()
+		module := moduleStats{
+			module: name,
+			level: config.Level,
+			prefix: config.Prefix,
+			emitted: make(map[Level]int64, len(logLevels)),
+			suppressed: make(map[Level]int64, len(logLevels)),
+		}
+
+		for _, l := range logLevels {
+			module.emitted[l], module.suppressed[l] = log.counts.get(l)
+		}
+
+		stats = append(stats, module)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].module < stats[j].module
+	})
+
+	return stats
+}
+
+var publishOnce sync.Once
+
+// Publishes the module loggers through expvar under the name "golog", so that
+// they show on /debug/vars, e.g.
+//
+//	"golog": {"db": {"level": "INFO", "prefix": "[db] ",
+//		"emitted": {"ERROR": 2, "INFO": 10, ...},
+//		"suppressed": {"DEBUG": 120, ...}}}
+//
+// where emitted and suppressed count the logs per level that passed or
+// failed the level check of the module. Safe to call more than once.
+func PublishExpvar() {
+	publishOnce.Do(func() {
+		expvar.Publish("golog", expvar.Func(expvarStats))
+	})
+}
+
+func expvarStats() interface{} {
+	modules := make(map[string]interface{})
+
+	for _, stats := range registryStats() {
+		emitted := make(map[string]int64, len(logLevels))
+		suppressed := make(map[string]int64, len(logLevels))
+		for _, l := range logLevels {
+			emitted[l.String()] = stats.emitted[l]
+			suppressed[l.String()] = stats.suppressed[l]
+		}
+
+		modules[stats.module] = map[string]interface{}{
+			"level": stats.level.String(),
+			"prefix": stats.prefix,
+			"emitted": emitted,
+			"suppressed": suppressed,
+		}
+	}
+
+	return modules
+}
@@ -0,0 +1,99 @@
+package golog
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+)
+
+func TestLogger_Counts(t *testing.T) {
+	mockExit(t)
+
+	log, _ := newLoggerWithMocks(LogConfig{Level: WARN})
+	child := log.With("request_id", 42)
+
+	log.Fatal("fatal")
+	log.Errorf("error %d", 1)
+	child.Errorw("error", "attempt", 2)
+	log.Warn("warning")
+	child.Info("info")
+	log.Debugf("debug")
+	log.Debugw("debug")
+	child.Verbose("verbose")
+
+	testCases := []struct {
+		Level Level
+		Emitted int64
+		Suppressed int64
+	}{
+		{FATAL, 1, 0},
+		{ERROR, 2, 0},
+		{WARN, 1, 0},
+		{INFO, 0, 1},
+		{DEBUG, 0, 2},
+		{VERBOSE, 0, 1},
+	}
+
+	for i, c := range testCases {
+		emitted, suppressed := log.counts.get(c.Level)
+		if emitted != c.Emitted || suppressed != c.Suppressed {
+			t.Errorf("TC %d: Expect %s to have %d emitted and %d suppressed, got %d and %d instead",
+				i,
+				c.Level,
+				c.Emitted,
+				c.Suppressed,
+				emitted,
+				suppressed,
+			)
+		}
+	}
+}
+
+func TestPublishExpvar(t *testing.T) {
+	resetRegistry(t)
+
+	Setup("db", LogConfig{
+		Prefix: "[database] ",
+		Level: NOLOG,
+	})
+
+	GetLogger("db").Error("suppressed")
+	GetLogger("db").Debug("suppressed")
+	GetLogger("db").Debug("suppressed")
+
+	PublishExpvar()
+	PublishExpvar()
+
+	variable := expvar.Get("golog")
+	if variable == nil {
+		t.Fatal("Expect golog to be published.")
+	}
+
+	var modules map[string]struct {
+		Level string
+		Prefix string
+		Emitted map[string]int64
+		Suppressed map[string]int64
+	}
+
+	if err := json.Unmarshal([]byte(variable.String()), &modules); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	db, ok := modules["db"]
+	if !ok || len(modules) != 1 {
+		t.Fatalf("Expect a single db module, got %+v", modules)
+	}
+
+	if db.Level != "NOLOG" || db.Prefix != "[database] " {
+		t.Errorf("Expect level NOLOG and prefix %q, got %s and %q", "[database] ", db.Level, db.Prefix)
+	}
+
+	if db.Suppressed["ERROR"] != 1 || db.Suppressed["DEBUG"] != 2 || db.Emitted["ERROR"] != 0 {
+		t.Errorf("Expect the suppressed logs to be counted, got %+v", db)
+	}
+
+	if len(db.Emitted) != 6 || len(db.Suppressed) != 6 {
+		t.Errorf("Expect every level to be listed, got %+v", db)
+	}
+}
@@ -0,0 +1,85 @@
+package golog
+
+import (
+	"log"
+	"strings"
+)
+
+// The number of stack frames between LogWriter.Write and the application code
+// calling the log package: Write is called by log.Logger's output, called by
+// e.g. log.Printf or log.Logger.Printf.
+const stdLogCallerDepth = 3
+
+// An io.Writer forwarding each line written to it into a module logger at a
+// fixed level, so that code writing to the standard log package carries the
+// prefix and follows the level and backend of the module. Lines at DEBUG or
+// VERBOSE also pass if glog's verbosity allows them, see HonorGlogVerbosity.
+// Created through NewLogWriter.
+type LogWriter struct {
+	log *logger
+	level Level
+}
+
+// Returns a writer forwarding each line into the module at level l, see
+// GetLogger. Each call to Write should hold whole lines, as the log package
+// writes them; empty lines are dropped.
+func NewLogWriter(module string, l Level) *LogWriter {
+	return &LogWriter{
+		log: GetLogger(module).(*logger),
+		level: l,
+	}
+}
+
+// Forwards each line of data as a record; never fails.
+func (writer *LogWriter) Write(data []byte) (int, error) {
+	config := writer.log.getConfig()
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" {
+			continue
+		}
+
+		enabled := config.allows(stdLogCallerDepth, writer.level)
+		writer.log.counts.add(writer.level, enabled)
+		if !enabled {
+			continue
+		}
+
+		config.backend().Log(stdLogCallerDepth, Record{
+			Time: now(),
+			Level: writer.level,
+			Module: writer.log.name,
+			Prefix: config.Prefix,
+			Message: line,
+		})
+	}
+
+	return len(data), nil
+}
+
+// Returns a *log.Logger writing into the module at level l, e.g. for the
+// ErrorLog of an http.Server. The logger adds neither a prefix nor flags,
+// since the backend of the module writes the time and location.
+func NewStdLogger(module string, l Level) *log.Logger {
+	return log.New(NewLogWriter(module, l), "", 0)
+}
+
+// Redirects the output of the standard log package into the module at level
+// l, so that every line of third-party code logging through it carries the
+// prefix of the module. The flags and prefix of the log package are cleared,
+// since the backend of the module writes the time and location.
+//
+// Returns a function restoring the previous output, flags and prefix.
+func RedirectStdLog(module string, l Level) (restore func()) {
+	writer, flags, prefix := log.Writer(), log.Flags(), log.Prefix()
+
+	log.SetOutput(NewLogWriter(module, l))
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	return func() {
+		log.SetOutput(writer)
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+	}
+}
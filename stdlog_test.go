@@ -0,0 +1,102 @@
+package golog
+
+import (
+	"log"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestLogWriter(t *testing.T) {
+	resetRegistry(t)
+	mockNow(t)
+
+	mockBackend := &MockBackend{}
+	Setup("http", LogConfig{
+		Level: WARN,
+		Prefix: "[http] ",
+		Backend: mockBackend,
+	})
+
+	input := "first line\n\nsecond line\n"
+	n, err := NewLogWriter("http", ERROR).Write([]byte(input))
+	if n != len(input) || err != nil {
+		t.Errorf("Expect the whole input to be written, got %d, %v", n, err)
+	}
+
+	expected := []Record{
+		{Time: testTime, Level: ERROR, Module: "http", Prefix: "[http] ", Message: "first line"},
+		{Time: testTime, Level: ERROR, Module: "http", Prefix: "[http] ", Message: "second line"},
+	}
+
+	if !reflect.DeepEqual(mockBackend.Records, expected) {
+		t.Errorf("Expect a record per line %+v, got %+v instead", expected, mockBackend.Records)
+	}
+
+	// the level of the module applies
+	NewLogWriter("http", INFO).Write([]byte("suppressed\n"))
+	if len(mockBackend.Records) != len(expected) {
+		t.Errorf("Expect lines below the module level to be suppressed, got %+v", mockBackend.Last())
+	}
+}
+
+func TestNewStdLogger(t *testing.T) {
+	resetRegistry(t)
+
+	mockBackend := &MockBackend{}
+	Setup("http", LogConfig{Level: INFO, Backend: mockBackend})
+
+	logger := NewStdLogger("http", WARN)
+	_, _, line, _ := runtime.Caller(0)
+	logger.Printf("TLS handshake error from %s", "10.0.0.1")
+
+	if mockBackend.Last().Message != "TLS handshake error from 10.0.0.1" || mockBackend.Last().Level != WARN {
+		t.Errorf("Expect a %s record of the line, got %+v", WARN, mockBackend.Last())
+	}
+
+	if filepath.Base(mockBackend.File) != "stdlog_test.go" || mockBackend.Line != line + 1 {
+		t.Errorf("Expect the record to be attributed to stdlog_test.go:%d, got %s:%d",
+			line + 1,
+			mockBackend.File,
+			mockBackend.Line,
+		)
+	}
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	resetRegistry(t)
+
+	mockBackend := &MockBackend{}
+	Setup("thirdparty", LogConfig{
+		Level: INFO,
+		Prefix: "[thirdparty] ",
+		Backend: mockBackend,
+	})
+
+	writer, flags := log.Writer(), log.Flags()
+	log.SetPrefix("lib: ")
+
+	restore := RedirectStdLog("thirdparty", INFO)
+	_, _, line, _ := runtime.Caller(0)
+	log.Println("connected")
+	restore()
+
+	if mockBackend.Last().Message != "connected" || mockBackend.Last().Prefix != "[thirdparty] " {
+		t.Errorf("Expect the line without flags nor prefix, got %+v", mockBackend.Last())
+	}
+
+	if filepath.Base(mockBackend.File) != "stdlog_test.go" || mockBackend.Line != line + 1 {
+		t.Errorf("Expect the record to be attributed to stdlog_test.go:%d, got %s:%d",
+			line + 1,
+			mockBackend.File,
+			mockBackend.Line,
+		)
+	}
+
+	if log.Writer() != writer || log.Flags() != flags || log.Prefix() != "lib: " {
+		t.Error("Expect the previous output, flags and prefix to be restored.")
+	}
+
+	log.SetPrefix("")
+}
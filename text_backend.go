@@ -0,0 +1,111 @@
+package golog
+
+import (
+	"io"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The defaults of TextFormat
+const (
+	defaultTimeFormat = "2006-01-02 15:04:05.000000"
+	defaultHeader = "{time} {level} {caller}] "
+)
+
+// Describes how a TextBackend writes records; the zero value gives lines such
+// as
+//
+//	2017-03-01 10:00:00.000000 INFO charge.go:42] [billing] request_id=42 charged card
+type TextFormat struct {
+	// the layout of the time of the record, as for time.Format; defaults to
+	// "2006-01-02 15:04:05.000000"
+	TimeFormat string
+
+	// written before the prefix, with the placeholders {time}, {level},
+	// {module} and {caller} (file:line) replaced by the values of the record;
+	// defaults to "{time} {level} {caller}] "
+	Header string
+}
+
+// A Backend writing each record as a line of text to an io.Writer, without
+// depending on glog: the header (see TextFormat), then the prefix, the fields
+// as key=value pairs, and the message. Created through NewTextBackend.
+//
+// Like JSONBackend, the backend does not buffer: each record is written to
+// the writer with a single Write call. Writers that buffer, such as a
+// *bufio.Writer, are flushed by Flush.
+//
+// Building with the noglog tag leaves glog out altogether, e.g. so that its
+// flags are not registered; modules without a backend then write text to
+// stderr in the default format, and HonorGlogVerbosity does nothing.
+type TextBackend struct {
+	format TextFormat
+
+	lock sync.Mutex
+	writer io.Writer
+}
+
+// Returns a backend writing lines of text to the writer, e.g. os.Stderr, in
+// the given format.
+func NewTextBackend(writer io.Writer, format TextFormat) *TextBackend {
+	if format.TimeFormat == "" {
+		format.TimeFormat = defaultTimeFormat
+	}
+
+	if format.Header == "" {
+		format.Header = defaultHeader
+	}
+
+	return &TextBackend{
+		format: format,
+		writer: writer,
+	}
+}
+
+// Flushes the writer if it implements Flusher, e.g. a *bufio.Writer.
+func (backend *TextBackend) Flush() error {
+	backend.lock.Lock()
+	defer backend.lock.Unlock()
+
+	if flusher, ok := backend.writer.(Flusher); ok {
+		return flusher.Flush()
+	}
+
+	return nil
+}
+
+func (backend *TextBackend) Log(depth int, record Record) {
+	caller := "???:1"
+	if _, file, line, ok := runtime.Caller(depth + 1); ok {
+		caller = filepath.Base(file) + ":" + strconv.Itoa(line)
+	}
+
+	header := strings.NewReplacer(
+		"{time}", record.Time.Format(backend.format.TimeFormat),
+		"{level}", record.Level.String(),
+		"{module}", record.Module,
+		"{caller}", caller,
+	).Replace(backend.format.Header)
+
+	var line strings.Builder
+	line.WriteString(header)
+	line.WriteString(record.Prefix)
+	if len(record.Fields) > 0 {
+		line.WriteString(formatFields(record.Fields))
+		line.WriteByte(' ')
+	}
+
+	line.WriteString(record.Message)
+	line.WriteByte('\n')
+
+	backend.lock.Lock()
+	defer backend.lock.Unlock()
+
+	io.WriteString(backend.writer, line.String())
+}
+
+var _ Backend = (*TextBackend)(nil)
+var _ Flusher = (*TextBackend)(nil)
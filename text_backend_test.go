@@ -0,0 +1,89 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTextBackend(t *testing.T) {
+	mockNow(t)
+	mockExit(t)
+
+	testCases := []struct {
+		Level string
+		Call func(log Logger)
+	}{
+		{"FATAL", func(log Logger) { log.Fatalf("%s", "hello") }},
+		{"ERROR", func(log Logger) { log.Error("hello") }},
+		{"WARN", func(log Logger) { log.Warnf("%s", "hello") }},
+		{"INFO", func(log Logger) { log.Info("hello") }},
+		{"DEBUG", func(log Logger) { log.Debugf("%s", "hello") }},
+		{"VERBOSE", func(log Logger) { log.Verbose("hello") }},
+	}
+
+	for i, c := range testCases {
+		var output bytes.Buffer
+		log := newLogger("billing", LogConfig{
+			Level: VERBOSE,
+			Prefix: "[billing] ",
+			Backend: NewTextBackend(&output, TextFormat{}),
+		})
+
+		c.Call(log)
+
+		expected := "2017-03-01 10:00:00.000000 " + c.Level + " " + callerOf(c.Call) + "] [billing] hello\n"
+		if output.String() != expected {
+			t.Errorf("TC %d: Expected output %q, actual output %q",
+				i,
+				expected,
+				output.String(),
+			)
+		}
+	}
+}
+
+func TestTextBackend_Format(t *testing.T) {
+	mockNow(t)
+
+	testCases := []struct {
+		Format TextFormat
+		Expected string
+	}{
+		{
+			Format: TextFormat{TimeFormat: "15:04"},
+			Expected: "10:00 WARN {caller}] [billing] request_id=42 user=\"John Smith\" charged card\n",
+		},
+		{
+			Format: TextFormat{Header: "{level} {module} {unknown}: "},
+			Expected: "WARN billing {unknown}: [billing] request_id=42 user=\"John Smith\" charged card\n",
+		},
+		{
+			Format: TextFormat{TimeFormat: "2006", Header: "[{time}] "},
+			Expected: "[2017] [billing] request_id=42 user=\"John Smith\" charged card\n",
+		},
+	}
+
+	for i, c := range testCases {
+		var output bytes.Buffer
+		log := newLogger("billing", LogConfig{
+			Level: INFO,
+			Prefix: "[billing] ",
+			Backend: NewTextBackend(&output, c.Format),
+		})
+
+		call := func() { log.With("request_id", 42).Warnw("charged card", "user", "John Smith") }
+		call()
+
+		// the caller is only known once the function literal exists
+		expected := strings.Replace(c.Expected, "{caller}", callerOf(call), 1)
+
+		if output.String() != expected {
+			t.Errorf("TC %d: Expected output %q, actual output %q",
+				i,
+				expected,
+				output.String(),
+			)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+//go:build !noglog
+
+package golog
+
+import (
+	"github.com/golang/glog"
+	"sync/atomic"
+)
+
+// Whether DEBUG and VERBOSE logs also pass when glog's verbosity allows them;
+// 1 if so. Accessed atomically, since it is read on every log.
+var honorGlogVerbosity int32
+
+// Makes the level check of DEBUG and VERBOSE logs also consult glog's -v and
+// -vmodule flags, so that they can be turned on the way glog users are used
+// to. The two levels map onto glog verbosity as
+//
+//	DEBUG    glog.V(1)
+//	VERBOSE  glog.V(2)
+//
+// A log passes if either the level of its module or glog allows it: e.g.
+// with -v=1, Debug logs of every module are written, even those of modules
+// setup at INFO or NOLOG, while Verbose logs still require the module to be
+// at VERBOSE. -vmodule matches the file of the code issuing the log.
+//
+// Logs written through a SlogHandler or a LogWriter are checked the same
+// way, at the level they map onto. Levels down to INFO are not affected.
+// Disabled by default, and does nothing when built with the noglog tag.
+func HonorGlogVerbosity(enabled bool) {
+	var value int32
+	if enabled {
+		value = 1
+	}
+
+	atomic.StoreInt32(&honorGlogVerbosity, value)
+}
+
+// Whether glog's verbosity allows a DEBUG or VERBOSE log, if enabled through
+// HonorGlogVerbosity. depth is the number of stack frames between the caller
+// and the application code issuing the log, see LogConfig.allows.
+func glogVerbose(depth int, l Level) bool {
+	if atomic.LoadInt32(&honorGlogVerbosity) == 0 {
+		return false
+	}
+
+	// glog matches -vmodule against the file of the application code, which
+	// is one frame further up from here than from the caller
+	return bool(glog.VDepth(depth + 1, glogVerbosity(l)))
+}
+
+// Returns the glog verbosity a level maps onto, e.g. 1 for DEBUG; only
+// meaningful for levels above INFO.
+func glogVerbosity(l Level) glog.Level {
+	return glog.Level(l - INFO)
+}
@@ -0,0 +1,160 @@
+//go:build !noglog
+
+package golog
+
+import (
+	"flag"
+	"log/slog"
+	"testing"
+)
+
+// Sets one of glog's flags for the duration of the test.
+func setGlogFlag(t *testing.T, name string, value string) {
+	t.Helper()
+
+	f := flag.Lookup(name)
+	previous := f.Value.String()
+	if err := f.Value.Set(value); err != nil {
+		t.Fatalf("Unable to set -%s: %s", name, err)
+	}
+
+	t.Cleanup(func() {
+		f.Value.Set(previous)
+	})
+}
+
+func TestHonorGlogVerbosity(t *testing.T) {
+	HonorGlogVerbosity(true)
+	defer HonorGlogVerbosity(false)
+
+	testCases := []struct {
+		Level Level
+		V string
+		Debug bool
+		Verbose bool
+	}{
+		{NOLOG, "0", false, false},
+		{NOLOG, "1", true, false},
+		{NOLOG, "2", true, true},
+		{INFO, "0", false, false},
+		{INFO, "1", true, false},
+		{INFO, "2", true, true},
+		{DEBUG, "0", true, false},
+		{DEBUG, "1", true, false},
+		{DEBUG, "2", true, true},
+		{VERBOSE, "0", true, true},
+		{VERBOSE, "1", true, true},
+		{VERBOSE, "2", true, true},
+	}
+
+	for i, c := range testCases {
+		setGlogFlag(t, "v", c.V)
+
+		log, mockBackend := newLoggerWithMocks(LogConfig{Level: c.Level})
+		log.Info("info")
+		log.Debug("debug")
+		log.Verbosef("%s", "verbose")
+
+		logged := map[string]bool{}
+		for _, record := range mockBackend.Records {
+			logged[record.Message] = true
+		}
+
+		// glog's verbosity never silences a log the level allows, and does
+		// not reach down to INFO
+		if logged["info"] != (c.Level >= INFO) || logged["debug"] != c.Debug || logged["verbose"] != c.Verbose {
+			t.Errorf("TC %d: Expect debug %t and verbose %t at level %s with -v=%s, got %v",
+				i,
+				c.Debug,
+				c.Verbose,
+				c.Level,
+				c.V,
+				logged,
+			)
+		}
+	}
+}
+
+func TestHonorGlogVerbosity_Disabled(t *testing.T) {
+	setGlogFlag(t, "v", "2")
+
+	log, mockBackend := newLoggerWithMocks(LogConfig{Level: INFO})
+	log.Debug("debug")
+	log.Verbosew("verbose")
+
+	if len(mockBackend.Records) != 0 {
+		t.Errorf("Expect glog's verbosity to be ignored by default, got %+v", mockBackend.Records)
+	}
+}
+
+func TestHonorGlogVerbosity_VModule(t *testing.T) {
+	HonorGlogVerbosity(true)
+	defer HonorGlogVerbosity(false)
+
+	testCases := []struct {
+		VModule string
+		Logged int
+	}{
+		// matched against the file issuing the log, not golog's own
+		{"verbosity_test=1", 1},
+		{"logger=2", 0},
+		{"other=2", 0},
+	}
+
+	for i, c := range testCases {
+		setGlogFlag(t, "vmodule", c.VModule)
+
+		log, mockBackend := newLoggerWithMocks(LogConfig{Level: INFO})
+		log.Debugw("debug")
+		log.Verbose("verbose")
+
+		if len(mockBackend.Records) != c.Logged {
+			t.Errorf("TC %d: Expect %d records with -vmodule=%s, got %+v",
+				i,
+				c.Logged,
+				c.VModule,
+				mockBackend.Records,
+			)
+		}
+	}
+}
+
+func TestHonorGlogVerbosity_Bridges(t *testing.T) {
+	resetRegistry(t)
+
+	HonorGlogVerbosity(true)
+	defer HonorGlogVerbosity(false)
+
+	mockBackend := &MockBackend{}
+	Setup("billing", LogConfig{Level: INFO, Backend: mockBackend})
+
+	slogger := slog.New(NewSlogHandler("billing"))
+	stdLogger := NewStdLogger("billing", DEBUG)
+
+	testCases := []struct {
+		VModule string
+		Logged int
+	}{
+		// matched against the file issuing the log, not the bridges
+		{"verbosity_test=1", 2},
+		{"slog=1,stdlog=1,logger=1", 0},
+		{"verbosity_test=0", 0},
+	}
+
+	for i, c := range testCases {
+		setGlogFlag(t, "vmodule", c.VModule)
+
+		mockBackend.Records = nil
+		slogger.Debug("from slog")
+		stdLogger.Print("from log")
+
+		if len(mockBackend.Records) != c.Logged {
+			t.Errorf("TC %d: Expect %d records with -vmodule=%s, got %+v",
+				i,
+				c.Logged,
+				c.VModule,
+				mockBackend.Records,
+			)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package golog
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The name of the module golog logs its own messages to, e.g. the summary of
+// a configuration reload.
+const internalModule = "golog"
+
+// Represents a configuration file (see LoadConfig) that is applied to the
+// module loggers again whenever it changes.
+type configFile struct {
+	path string
+
+	// guards the fields below, which describe the configuration last applied
+	lock sync.Mutex
+	data []byte
+	config *fileConfig
+}
+
+// Watches a configuration file, and reapplies it to the module loggers
+// whenever it changes. Created through WatchConfigFile.
+type ConfigWatcher struct {
+	*configFile
+	interval time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+	stopOnce sync.Once
+}
+
+// Loads the configuration file, then polls it every interval and reapplies it
+// when it changes. Levels and prefixes of existing loggers are updated in
+// place, so that logs issued during a reload are not lost.
+//
+// Each reload logs an INFO line to the "golog" module summarising what
+// changed. A file that cannot be read or is invalid is reported as an ERROR,
+// and the previous configuration is kept. Levels, prefixes and outputs
+// removed from the file revert to the defaults of GetLogger, while the rest
+// of the configuration of the module, e.g. a FatalHandler set through Setup,
+// is kept.
+//
+// Returns an error if the interval is not positive, or if the file cannot be
+// loaded initially.
+func WatchConfigFile(path string, interval time.Duration) (*ConfigWatcher, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("golog: invalid polling interval %s", interval)
+	}
+
+	watcher := &ConfigWatcher{
+		configFile: &configFile{path: path},
+		interval: interval,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	if err := watcher.reload(); err != nil {
+		return nil, err
+	}
+
+	go watcher.run()
+	return watcher, nil
+}
+
+// Stops watching the file; the configuration last applied stays in place.
+func (watcher *ConfigWatcher) Stop() {
+	watcher.stopOnce.Do(func() {
+		close(watcher.stop)
+	})
+
+	<-watcher.done
+}
+
+func (watcher *ConfigWatcher) run() {
+	defer close(watcher.done)
+
+	ticker := time.NewTicker(watcher.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-watcher.stop:
+			return
+
+		case <-ticker.C:
+			if err := watcher.reload(); err != nil {
+				GetLogger(internalModule).Errorf("keeping the previous logging configuration: %s", err)
+			}
+		}
+	}
+}
+
+// Reapplies the file if it changed since it was last applied. The first
+// reload applies the file without logging a summary.
+func (file *configFile) reload() error {
+	return file.load(false)
+}
+
+// Reads the file and applies it, even if it did not change since it was last
+// applied when always is set, e.g. to undo levels changed through SetLevel.
+func (file *configFile) load(always bool) error {
+	file.lock.Lock()
+	defer file.lock.Unlock()
+
+	// configuration files are small enough to be compared as a whole, which
+	// unlike modification times catches every change
+	data, err := os.ReadFile(file.path)
+	if err != nil {
+		return err
+	}
+
+	if !always && file.config != nil && bytes.Equal(data, file.data) {
+		return nil
+	}
+
+	config, err := parseConfig(data)
+	if err != nil {
+		// remember the contents so that the same error is not reported on
+		// every poll, but keep the configuration
+		file.data = data
+		return fmt.Errorf("%s: %s", file.path, err)
+	}
+
+	changes := diffConfig(file.config, config)
+
+	loggersLock.Lock()
+	if file.config != nil {
+		unapplyConfig(file.config, config)
+	}
+	applyConfig(config)
+	loggersLock.Unlock()
+
+	initial := file.config == nil
+	file.data, file.config = data, config
+
+	if !initial && len(changes) > 0 {
+		GetLogger(internalModule).Infof("reloaded logging configuration from %s: %s",
+			file.path,
+			strings.Join(changes, "; "),
+		)
+	}
+
+	return nil
+}
+
+// Reverts what the previous configuration set and the next one does not;
+// must be called with loggersLock held for writing.
+func unapplyConfig(previous, next *fileConfig) {
+	if previous.defaultLevel != nil && next.defaultLevel == nil {
+		removeLevelRule("")
+	}
+
+	if previous.defaultBackend != nil && next.defaultBackend == nil {
+		setBackendRule(nil)
+	}
+
+	// only the settings of the previous file are reverted, so that the ones
+	// made in code are kept
+	for name, module := range previous.modules {
+		nextModule := next.modules[name]
+
+		if module.level != nil && nextModule.level == nil {
+			unsetLevel(name)
+		}
+
+		if module.prefix != nil && nextModule.prefix == nil {
+			setPrefix(name, "[" + name + "] ")
+		}
+
+		if module.backend != nil && nextModule.backend == nil {
+			setBackend(name, nil)
+		}
+	}
+}
+
+// Describes the differences between two configurations, e.g.
+// "db level: DEBUG -> INFO". previous may be nil.
+func diffConfig(previous, next *fileConfig) []string {
+	if previous == nil {
+		previous = &fileConfig{}
+	}
+
+	var changes []string
+
+	if describeLevel(previous.defaultLevel) != describeLevel(next.defaultLevel) {
+		changes = append(changes, fmt.Sprintf("default level: %s -> %s",
+			describeLevel(previous.defaultLevel),
+			describeLevel(next.defaultLevel),
+		))
+	}
+
+	if previous.defaultOutput != next.defaultOutput {
+		changes = append(changes, fmt.Sprintf("default output: %s -> %s",
+			describeOutput(previous.defaultOutput),
+			describeOutput(next.defaultOutput),
+		))
+	}
+
+	names := make([]string, 0, len(previous.modules) + len(next.modules))
+	for name := range previous.modules {
+		names = append(names, name)
+	}
+
+	for name := range next.modules {
+		if _, ok := previous.modules[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		before, existed := previous.modules[name]
+		after, exists := next.modules[name]
+
+		switch {
+		case !existed:
+			changes = append(changes, name + " added")
+
+		case !exists:
+			changes = append(changes, name + " removed")
+		}
+
+		if describeLevel(before.level) != describeLevel(after.level) {
+			changes = append(changes, fmt.Sprintf("%s level: %s -> %s",
+				name,
+				describeLevel(before.level),
+				describeLevel(after.level),
+			))
+		}
+
+		if describePrefix(before.prefix) != describePrefix(after.prefix) {
+			changes = append(changes, fmt.Sprintf("%s prefix: %s -> %s",
+				name,
+				describePrefix(before.prefix),
+				describePrefix(after.prefix),
+			))
+		}
+
+		if before.output != after.output {
+			changes = append(changes, fmt.Sprintf("%s output: %s -> %s",
+				name,
+				describeOutput(before.output),
+				describeOutput(after.output),
+			))
+		}
+	}
+
+	return changes
+}
+
+func describeLevel(l *Level) string {
+	if l == nil {
+		return "unset"
+	}
+
+	return l.String()
+}
+
+func describePrefix(prefix *string) string {
+	if prefix == nil {
+		return "unset"
+	}
+
+	return fmt.Sprintf("%q", *prefix)
+}
+
+func describeOutput(output string) string {
+	if output == "" {
+		return "unset"
+	}
+
+	return output
+}
@@ -0,0 +1,297 @@
+package golog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, path string, document string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(document), 0644); err != nil {
+		t.Fatalf("Unable to write config: %s", err)
+	}
+}
+
+func TestConfigWatcher_Reload(t *testing.T) {
+	resetRegistry(t)
+
+	internal := &MockBackend{}
+	Setup(internalModule, LogConfig{
+		Level: INFO,
+		Backend: internal,
+	})
+
+	path := filepath.Join(t.TempDir(), "logging.yaml")
+	writeConfig(t, path, `
+default:
+  level: INFO
+modules:
+  db:
+    level: DEBUG
+    prefix: "[database] "
+  http:
+    level: WARN
+`)
+
+	// a long interval, so that the test drives the reloads
+	watcher, err := WatchConfigFile(path, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer watcher.Stop()
+
+	db := GetLogger("db").(*logger)
+	http := GetLogger("http").(*logger)
+	cache := GetLogger("cache").(*logger)
+
+	if db.getConfig().Level != DEBUG || http.getConfig().Level != WARN || cache.getConfig().Level != INFO {
+		t.Fatal("Expect the initial configuration to be applied.")
+	}
+
+	if len(internal.Records) != 0 {
+		t.Errorf("Expect nothing to be logged initially, got %+v", internal.Records)
+	}
+
+	writeConfig(t, path, `
+default:
+  level: ERROR
+modules:
+  db:
+    level: VERBOSE
+  billing:
+    level: WARN
+`)
+
+	if err := watcher.reload(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	checkLoggers(t, []struct {
+		LoggerName string
+		Level Level
+		Prefix string
+	}{
+		{"db", VERBOSE, "[db] "},
+		{"http", ERROR, "[http] "},
+		{"cache", ERROR, "[cache] "},
+		{"billing", WARN, "[billing] "},
+		{"billing.invoice", WARN, "[billing.invoice] "},
+	})
+
+	// existing handles are updated in place
+	if db != GetLogger("db") || http != GetLogger("http") {
+		t.Error("Expect the loggers to be updated in place.")
+	}
+
+	summary := internal.Last()
+	if summary.Level != INFO {
+		t.Errorf("Expect the summary to be logged as INFO, got %s", summary.Level)
+	}
+
+	expected := "reloaded logging configuration from " + path + ": " +
+		"default level: INFO -> ERROR; " +
+		"billing added; billing level: unset -> WARN; " +
+		`db level: DEBUG -> VERBOSE; db prefix: "[database] " -> unset; ` +
+		"http removed; http level: WARN -> unset"
+
+	if summary.Message != expected {
+		t.Errorf("Expected summary %q, actual: %q", expected, summary.Message)
+	}
+
+	// nothing is logged when the file did not change
+	records := len(internal.Records)
+	if err := watcher.reload(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(internal.Records) != records {
+		t.Errorf("Expect nothing to be logged without changes, got %+v", internal.Last())
+	}
+}
+
+func TestConfigWatcher_Invalid(t *testing.T) {
+	resetRegistry(t)
+
+	path := filepath.Join(t.TempDir(), "logging.yaml")
+	writeConfig(t, path, "modules:\n  db:\n    level: DEBUG\n")
+
+	watcher, err := WatchConfigFile(path, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer watcher.Stop()
+
+	writeConfig(t, path, "modules:\n  db:\n    level: LOUD\n")
+
+	err = watcher.reload()
+	if err == nil || !strings.Contains(err.Error(), `line 3: unknown level "LOUD"`) {
+		t.Errorf("Expected an error for the invalid file, got %v", err)
+	}
+
+	if GetLogger("db").(*logger).getConfig().Level != DEBUG {
+		t.Error("Expect the previous configuration to be kept.")
+	}
+
+	// the same error is not reported twice
+	if err := watcher.reload(); err != nil {
+		t.Errorf("Expected the invalid file to be reported once, got %s", err)
+	}
+
+	// a fixed file is applied again
+	writeConfig(t, path, "modules:\n  db:\n    level: ERROR\n")
+	if err := watcher.reload(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if GetLogger("db").(*logger).getConfig().Level != ERROR {
+		t.Error("Expect the fixed configuration to be applied.")
+	}
+}
+
+func TestConfigWatcher_KeepsCodeSettings(t *testing.T) {
+	resetRegistry(t)
+
+	backend := &MockBackend{}
+	Setup("db", LogConfig{
+		Level: WARN,
+		Prefix: "[db] ",
+		Backend: backend,
+		OnFatal: PanicOnFatal,
+	})
+
+	path := filepath.Join(t.TempDir(), "logging.yaml")
+	writeConfig(t, path, "modules:\n  db:\n    level: DEBUG\n    prefix: \"[database] \"\n")
+
+	watcher, err := WatchConfigFile(path, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer watcher.Stop()
+
+	writeConfig(t, path, "default:\n  level: ERROR\n")
+	if err := watcher.reload(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	// the level and prefix set by the file revert, the rest is kept
+	config := GetLogger("db").(*logger).getConfig()
+	if config.Level != ERROR || config.Prefix != "[db] " {
+		t.Errorf("Expect the level and prefix of the file to revert, got %s and %q",
+			config.Level,
+			config.Prefix,
+		)
+	}
+
+	if config.Backend != backend || config.OnFatal == nil {
+		t.Errorf("Expect the backend and fatal handler set in code to be kept, got %+v", config)
+	}
+}
+
+func TestConfigWatcher_DefaultOutput(t *testing.T) {
+	resetRegistry(t)
+
+	internal := &MockBackend{}
+	Setup(internalModule, LogConfig{
+		Level: INFO,
+		Backend: internal,
+	})
+
+	path := filepath.Join(t.TempDir(), "logging.yaml")
+	writeConfig(t, path, "default:\n  output: stdout\n")
+
+	watcher, err := WatchConfigFile(path, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer watcher.Stop()
+
+	cache := GetLogger("cache").(*logger)
+	if _, ok := cache.getConfig().backend().(*JSONBackend); !ok {
+		t.Errorf("Expect cache to follow the default output, got %#v", cache.getConfig().backend())
+	}
+
+	writeConfig(t, path, "default:\n  level: INFO\n")
+	if err := watcher.reload(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if cache.getConfig().backend() != defaultBackend() {
+		t.Errorf("Expect cache to revert to the default backend, got %#v", cache.getConfig().backend())
+	}
+
+	expected := "default output: stdout/json -> unset"
+	if !strings.Contains(internal.Last().Message, expected) {
+		t.Errorf("Expect the summary %q to contain %q", internal.Last().Message, expected)
+	}
+}
+
+func TestWatchConfigFile_Errors(t *testing.T) {
+	resetRegistry(t)
+
+	if _, err := WatchConfigFile(filepath.Join(t.TempDir(), "missing.yaml"), time.Hour); err == nil {
+		t.Error("Expected an error for a missing file.")
+	}
+
+	path := filepath.Join(t.TempDir(), "logging.yaml")
+	writeConfig(t, path, "default:\n  level: LOUD\n")
+
+	if _, err := WatchConfigFile(path, time.Hour); err == nil {
+		t.Error("Expected an error for an invalid file.")
+	}
+
+	writeConfig(t, path, "default:\n  level: INFO\n")
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if _, err := WatchConfigFile(path, interval); err == nil {
+			t.Errorf("Expected an error for the interval %s.", interval)
+		}
+	}
+}
+
+func TestWatchConfigFile_Polling(t *testing.T) {
+	resetRegistry(t)
+
+	path := filepath.Join(t.TempDir(), "logging.yaml")
+	writeConfig(t, path, "modules:\n  db:\n    level: DEBUG\n")
+
+	watcher, err := WatchConfigFile(path, 10 * time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer watcher.Stop()
+
+	db := GetLogger("db")
+
+	// keep logging while the configuration changes
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				db.Verbose("in flight")
+			}
+		}
+	}()
+
+	writeConfig(t, path, "modules:\n  db:\n    level: ERROR\n")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for db.(*logger).getConfig().Level != ERROR {
+		if time.Now().After(deadline) {
+			t.Fatal("Expect the change to be picked up by polling.")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	close(stop)
+	<-done
+}